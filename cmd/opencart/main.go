@@ -10,6 +10,7 @@ import (
 	"ocapi/internal/config"
 	"ocapi/internal/database"
 	"ocapi/internal/http-server/api"
+	"ocapi/internal/imagestore"
 	"ocapi/internal/lib/logger"
 	"ocapi/internal/lib/sl"
 	"os"
@@ -32,7 +33,28 @@ func main() {
 
 	handler := core.New(lg)
 	handler.SetAuthKey(conf.Listen.ApiKey)
-	handler.SetImageParameters(conf.Images.Path, conf.Images.Url)
+	images, err := imagestore.New(conf)
+	if err != nil {
+		lg.Error("image store", sl.Err(err))
+		return
+	}
+	handler.SetImageParameters(images, conf.Images.Url)
+	cache, err := imagestore.NewCache(conf)
+	if err != nil {
+		lg.Error("image cache", sl.Err(err))
+		return
+	}
+	if err = handler.SetImageCache(cache, conf.Images.Cache.Sizes); err != nil {
+		lg.Error("image cache", sl.Err(err))
+		return
+	}
+	cleanup := conf.Images.Cleanup
+	if err = handler.SetImageCleanup(cleanup.Pattern, cleanup.Quarantine, cleanup.RetentionDays); err != nil {
+		lg.Error("image cleanup", sl.Err(err))
+		return
+	}
+	handler.SetImageLimits(conf.Images.MaxWidth, conf.Images.MaxHeight, conf.Images.Quality)
+	handler.SetDownloadPath(conf.Download.Path)
 
 	db, err := database.NewSQLClient(conf)
 	if err != nil {
@@ -60,6 +82,19 @@ func main() {
 				}
 			}
 		}()
+
+		if conf.Product.SpecialPurge > 0 {
+			go func() {
+				ticker := time.NewTicker(conf.Product.SpecialPurge)
+				defer ticker.Stop()
+
+				for range ticker.C {
+					if _, err := handler.PurgeExpiredSpecials(); err != nil {
+						lg.Error("purge specials", sl.Err(err))
+					}
+				}
+			}()
+		}
 	}
 
 	//if conf.Telegram.Enabled {
@@ -13,7 +13,8 @@ type Attribute struct {
 }
 
 type AttributeDescription struct {
-	LanguageId int64  `json:"language_id" validate:"required"`
+	LanguageId int64  `json:"language_id" validate:"required_without=Language"`
+	Language   string `json:"language,omitempty"`
 	Name       string `json:"name" validate:"required"`
 }
 
@@ -1,12 +1,11 @@
 package entity
 
 type BatchResult struct {
-	BatchUid     string `json:"batch_uid"`
-	Success      bool   `json:"status"`
-	Message      string `json:"message"`
-	Products     int    `json:"products"`
-	Categories   int    `json:"categories"`
-	DeletedFiles int    `json:"deleted_files"`
+	BatchUid   string `json:"batch_uid"`
+	Success    bool   `json:"status"`
+	Message    string `json:"message"`
+	Products   int    `json:"products"`
+	Categories int    `json:"categories"`
 }
 
 func NewBatchResult(batchUid string, err error) *BatchResult {
@@ -6,11 +6,13 @@ import (
 )
 
 type CategoryData struct {
-	CategoryUID string `json:"category_uid" validate:"required"`
-	ParentUID   string `json:"parent_uid"`
-	SortOrder   int    `json:"sort_order"`
-	Menu        bool   `json:"menu"`
-	Active      bool   `json:"active"`
+	CategoryUID string   `json:"category_uid" validate:"required"`
+	ParentUID   string   `json:"parent_uid"`
+	SortOrder   int      `json:"sort_order"`
+	Menu        bool     `json:"menu"`
+	Active      bool     `json:"active"`
+	Stores      []int64  `json:"stores"`      // store IDs to publish the category to
+	StoreCodes  []string `json:"store_codes"` // configured store codes, alternative to IDs
 }
 
 func (c *CategoryData) Bind(_ *http.Request) error {
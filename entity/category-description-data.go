@@ -7,9 +7,11 @@ import (
 
 type CategoryDescriptionData struct {
 	CategoryUid string `json:"category_uid" validate:"required"`
-	LanguageId  int64  `json:"language_id" validate:"required"`
+	LanguageId  int64  `json:"language_id" validate:"required_without=Language"`
+	Language    string `json:"language,omitempty"`
 	Name        string `json:"name" validate:"required"`
 	Description string `json:"description,omitempty"`
+	SeoKeyword  string `json:"seo_keyword,omitempty"`
 }
 
 func (c *CategoryDescriptionData) Bind(_ *http.Request) error {
@@ -0,0 +1,8 @@
+package entity
+
+// CategoryRepairResult reports a rebuild of the category paths; categories with a missing parent
+// or in a parent loop get no path and are listed as skipped
+type CategoryRepairResult struct {
+	Categories int     `json:"categories"`
+	Skipped    []int64 `json:"skipped,omitempty"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// datesOverlap reports whether two date ranges intersect, bounds included;
+// a zero date means the range is open on that side.
+func datesOverlap(start, end, otherStart, otherEnd time.Time) bool {
+	startsBeforeOtherEnds := otherEnd.IsZero() || start.IsZero() || !start.After(otherEnd)
+	otherStartsBeforeEnds := end.IsZero() || otherStart.IsZero() || !otherStart.After(end)
+	return startsBeforeOtherEnds && otherStartsBeforeEnds
+}
@@ -0,0 +1,119 @@
+package entity
+
+import "time"
+
+// Dictionary is a cached reference list of the shop configuration, e.g. order statuses or tax classes.
+// Items hold the item type of the list, e.g. []*StoreItem for stores.
+type Dictionary struct {
+	Name     string      `json:"name"`
+	Items    interface{} `json:"items"`
+	Size     int         `json:"-"`
+	LoadedAt time.Time   `json:"loaded_at"`
+}
+
+// DictionaryItem is an entry of a reference list as read from the database. Name is given in the first
+// shop language, Names holds the localized names by language code; the remaining fields are set where
+// they apply.
+type DictionaryItem struct {
+	Id        int64
+	Code      string // store code, language code, ISO code or unit
+	Name      string
+	Names     map[string]string
+	Url       string  // stores
+	CountryId int64   // zones
+	Value     float64 // weight and length classes, relative to the default unit
+	Status    bool    // languages, countries and zones
+}
+
+// StoreItem is an entry of the stores list; Code is empty if the store has no code in store.codes
+type StoreItem struct {
+	Id   int64  `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+	Url  string `json:"url"`
+}
+
+// LanguageItem is an entry of the languages list
+type LanguageItem struct {
+	Id     int64  `json:"id"`
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Status bool   `json:"status"`
+}
+
+// NamedItem is an entry of a list with names only: customer groups, order and stock statuses, tax classes
+type NamedItem struct {
+	Id    int64             `json:"id"`
+	Name  string            `json:"name"`
+	Names map[string]string `json:"names,omitempty"`
+}
+
+// UnitItem is an entry of the weight or length classes list
+type UnitItem struct {
+	Id    int64             `json:"id"`
+	Code  string            `json:"code"`
+	Name  string            `json:"name"`
+	Names map[string]string `json:"names,omitempty"`
+	Value float64           `json:"value"`
+}
+
+// CountryItem is an entry of the countries list; Names are set by OpenCart 4.1 and later
+type CountryItem struct {
+	Id     int64             `json:"id"`
+	Code   string            `json:"code"`
+	Name   string            `json:"name"`
+	Names  map[string]string `json:"names,omitempty"`
+	Status bool              `json:"status"`
+}
+
+// ZoneItem is an entry of the zones list; Names are set by OpenCart 4.1 and later
+type ZoneItem struct {
+	Id        int64             `json:"id"`
+	CountryId int64             `json:"country_id"`
+	Code      string            `json:"code"`
+	Name      string            `json:"name"`
+	Names     map[string]string `json:"names,omitempty"`
+	Status    bool              `json:"status"`
+}
+
+// NewDictionary builds the dictionary with the items converted to the item type of the list
+func NewDictionary(name string, items []*DictionaryItem, loadedAt time.Time) *Dictionary {
+	return &Dictionary{Name: name, Items: dictionaryItems(name, items), Size: len(items), LoadedAt: loadedAt}
+}
+
+func dictionaryItems(name string, items []*DictionaryItem) interface{} {
+	switch name {
+	case "stores":
+		return convertItems(items, func(i *DictionaryItem) *StoreItem {
+			return &StoreItem{Id: i.Id, Code: i.Code, Name: i.Name, Url: i.Url}
+		})
+	case "languages":
+		return convertItems(items, func(i *DictionaryItem) *LanguageItem {
+			return &LanguageItem{Id: i.Id, Code: i.Code, Name: i.Name, Status: i.Status}
+		})
+	case "weight_classes", "length_classes":
+		return convertItems(items, func(i *DictionaryItem) *UnitItem {
+			return &UnitItem{Id: i.Id, Code: i.Code, Name: i.Name, Names: i.Names, Value: i.Value}
+		})
+	case "countries":
+		return convertItems(items, func(i *DictionaryItem) *CountryItem {
+			return &CountryItem{Id: i.Id, Code: i.Code, Name: i.Name, Names: i.Names, Status: i.Status}
+		})
+	case "zones":
+		return convertItems(items, func(i *DictionaryItem) *ZoneItem {
+			return &ZoneItem{Id: i.Id, CountryId: i.CountryId, Code: i.Code, Name: i.Name, Names: i.Names, Status: i.Status}
+		})
+	default:
+		return convertItems(items, func(i *DictionaryItem) *NamedItem {
+			return &NamedItem{Id: i.Id, Name: i.Name, Names: i.Names}
+		})
+	}
+}
+
+func convertItems[T any](items []*DictionaryItem, convert func(*DictionaryItem) T) []T {
+	converted := make([]T, 0, len(items))
+	for _, item := range items {
+		converted = append(converted, convert(item))
+	}
+	return converted
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"net/http"
+	"ocapi/internal/lib/validate"
+)
+
+// Download is a downloadable file (e-book, firmware) uploaded as base64 data
+type Download struct {
+	DownloadUid  string                 `json:"download_uid" validate:"required"`
+	FileName     string                 `json:"file_name" validate:"required,max=127"` // name shown to customers; the masked name must fit filename varchar(160)
+	FileData     string                 `json:"file_data" validate:"required,base64"`
+	Descriptions []*DownloadDescription `json:"descriptions" validate:"omitempty,dive"`
+}
+
+type DownloadDescription struct {
+	LanguageId int64  `json:"language_id" validate:"required_without=Language"`
+	Language   string `json:"language,omitempty"`
+	Name       string `json:"name" validate:"required,max=64"`
+}
+
+type DownloadRequest struct {
+	Data []*Download `json:"data" validate:"required,dive"`
+}
+
+func (d *DownloadRequest) Bind(_ *http.Request) error {
+	return validate.Struct(d)
+}
+
+// DownloadData is a download file stored in the downloads directory
+type DownloadData struct {
+	DownloadUid  string
+	Filename     string // stored file name: mask with a random suffix
+	Mask         string // original file name
+	Descriptions []*DownloadDescription
+}
+
+// ProductDownload sets the complete list of downloads linked to a product
+type ProductDownload struct {
+	ProductUid   string   `json:"product_uid" validate:"required"`
+	DownloadUids []string `json:"download_uids" validate:"dive,required"` // empty list unlinks all downloads
+}
+
+type ProductDownloadRequest struct {
+	Data []*ProductDownload `json:"data" validate:"required,dive"`
+}
+
+func (p *ProductDownloadRequest) Bind(_ *http.Request) error {
+	return validate.Struct(p)
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// IdOrName references an OpenCart dictionary entry (tax class, stock status, ...) by numeric ID
+// or by name; in JSON both numbers and strings are accepted
+type IdOrName string
+
+func (v *IdOrName) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*v = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return err
+		}
+		*v = IdOrName(name)
+		return nil
+	}
+	var id json.Number
+	if err := json.Unmarshal(data, &id); err != nil {
+		return err
+	}
+	*v = IdOrName(id.String())
+	return nil
+}
+
+// Id returns the numeric ID if the value is one
+func (v IdOrName) Id() (int64, bool) {
+	id, err := strconv.ParseInt(string(v), 10, 64)
+	return id, err == nil
+}
@@ -0,0 +1,18 @@
+package entity
+
+// ImageCleanupReport describes a run of the orphan image cleanup
+type ImageCleanupReport struct {
+	DryRun      bool     `json:"dry_run"`
+	Orphans     []string `json:"orphans"`     // files not used by any product; moved to quarantine unless dry run
+	Purged      []string `json:"purged"`      // quarantined files deleted after the retention period
+	Quarantined int      `json:"quarantined"` // files kept in quarantine after the run
+	Skipped     int      `json:"skipped"`     // files outside the OCAPI naming scheme, left untouched
+}
+
+// QuarantinedImage is an orphan image file moved to quarantine by the cleanup
+type QuarantinedImage struct {
+	File          string `json:"file"`
+	Path          string `json:"path"`           // location in the image store, e.g. .quarantine/2025-03-24/uid.jpg
+	QuarantinedAt string `json:"quarantined_at"` // date the file was moved
+	PurgeAfter    string `json:"purge_after"`    // date from which the file is deleted by the cleanup
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "errors"
+
+var (
+	// ErrNotFound marks errors caused by a referenced entity that does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrInvalid marks errors caused by request data that cannot be applied
+	ErrInvalid = errors.New("invalid data")
+)
+
+const (
+	ItemErrorNotFound = "not_found"
+	ItemErrorInvalid  = "invalid"
+	ItemErrorFailed   = "failed"
+)
+
+// ItemResult reports the outcome of saving a single item of a bulk request
+type ItemResult struct {
+	Uid     string `json:"uid"`
+	Success bool   `json:"success"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func NewItemResult(uid string, err error) *ItemResult {
+	if err == nil {
+		return &ItemResult{
+			Uid:     uid,
+			Success: true,
+		}
+	}
+	code := ItemErrorFailed
+	switch {
+	case errors.Is(err, ErrNotFound):
+		code = ItemErrorNotFound
+	case errors.Is(err, ErrInvalid):
+		code = ItemErrorInvalid
+	}
+	return &ItemResult{
+		Uid:     uid,
+		Success: false,
+		Code:    code,
+		Message: err.Error(),
+	}
+}
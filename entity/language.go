@@ -0,0 +1,11 @@
+package entity
+
+// Language is a shop language; Code (e.g. "en-gb") is accepted instead of a language ID in requests
+type Language struct {
+	LanguageId int64  `json:"language_id"`
+	Name       string `json:"name"`
+	Code       string `json:"code"`
+	Locale     string `json:"locale"`
+	SortOrder  int64  `json:"sort_order"`
+	Status     bool   `json:"status"`
+}
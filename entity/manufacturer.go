@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"net/http"
+	"ocapi/internal/lib/validate"
+)
+
+// Manufacturer is a brand identified by manufacturer_uid
+type Manufacturer struct {
+	Uid          string                     `json:"manufacturer_uid" validate:"required,max=64"`
+	Name         string                     `json:"name" validate:"required,max=64"`
+	Image        *string                    `json:"image" validate:"omitempty,max=255"` // logo path in the images directory, e.g. catalog/brand/acme.png; unchanged if omitted
+	SortOrder    int                        `json:"sort_order"`
+	Stores       []int64                    `json:"stores"`
+	StoreCodes   []string                   `json:"store_codes"`
+	Descriptions []*ManufacturerDescription `json:"descriptions" validate:"omitempty,dive"`
+}
+
+// ManufacturerDescription holds the SEO keyword of a manufacturer in a language and, where the
+// manufacturer_description table exists (ocStore), its description and meta data
+type ManufacturerDescription struct {
+	LanguageId      int64  `json:"language_id" validate:"required_without=Language"`
+	Language        string `json:"language,omitempty"`
+	SeoKeyword      string `json:"seo_keyword" validate:"omitempty,max=255"`
+	Description     string `json:"description"`
+	MetaTitle       string `json:"meta_title" validate:"omitempty,max=255"`
+	MetaH1          string `json:"meta_h1" validate:"omitempty,max=255"`
+	MetaDescription string `json:"meta_description" validate:"omitempty,max=255"`
+	MetaKeyword     string `json:"meta_keyword" validate:"omitempty,max=255"`
+}
+
+type ManufacturerRequest struct {
+	Data []*Manufacturer `json:"data" validate:"required,dive"`
+}
+
+func (m *ManufacturerRequest) Bind(_ *http.Request) error {
+	return validate.Struct(m)
+}
+
+// ManufacturerRef references a manufacturer by UID or, for brands created without one, by ID
+type ManufacturerRef struct {
+	Uid string `json:"manufacturer_uid" validate:"required_without=Id"`
+	Id  int64  `json:"manufacturer_id" validate:"required_without=Uid"`
+}
+
+// ManufacturerMerge moves the products of a duplicate manufacturer to another one and deletes the duplicate
+type ManufacturerMerge struct {
+	Source ManufacturerRef `json:"source"`
+	Target ManufacturerRef `json:"target"`
+}
+
+func (m *ManufacturerMerge) Bind(_ *http.Request) error {
+	return validate.Struct(m)
+}
+
+type ManufacturerMergeResult struct {
+	ManufacturerId int64 `json:"manufacturer_id"` // the kept manufacturer
+	Products       int64 `json:"products"`        // products moved from the duplicate
+}
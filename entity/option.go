@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"net/http"
+	"ocapi/internal/lib/validate"
+)
+
+type Option struct {
+	Uid          string               `json:"option_uid" validate:"required"`
+	Type         string               `json:"type" validate:"required,oneof=select radio checkbox text textarea file date time datetime"`
+	SortOrder    int64                `json:"sort_order"`
+	Descriptions []*OptionDescription `json:"descriptions" validate:"required,dive"`
+	Values       []*OptionValue       `json:"values" validate:"omitempty,dive"`
+}
+
+type OptionDescription struct {
+	LanguageId int64  `json:"language_id" validate:"required_without=Language"`
+	Language   string `json:"language,omitempty"`
+	Name       string `json:"name" validate:"required"`
+}
+
+type OptionValue struct {
+	Uid          string               `json:"option_value_uid" validate:"required"`
+	Image        string               `json:"image"`
+	SortOrder    int64                `json:"sort_order"`
+	Descriptions []*OptionDescription `json:"descriptions" validate:"required,dive"`
+}
+
+type OptionDataRequest struct {
+	Data []*Option `json:"data" validate:"required,dive"`
+}
+
+func (r *OptionDataRequest) Bind(_ *http.Request) error {
+	return validate.Struct(r)
+}
@@ -8,7 +8,8 @@ import (
 type ProductAttribute struct {
 	ProductUid   string `json:"product_uid" validate:"required"`
 	AttributeUid string `json:"attribute_uid" validate:"required"`
-	LanguageId   int64  `json:"language_id" validate:"required"`
+	LanguageId   int64  `json:"language_id" validate:"required_without=Language"`
+	Language     string `json:"language,omitempty"`
 	Text         string `json:"text" validate:"required"`
 }
 
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProductCursor is the position of the last product of a page ordered by date_modified and product_id
+type ProductCursor struct {
+	DateModified time.Time
+	ProductId    int64
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c *ProductCursor) Encode() string {
+	value := fmt.Sprintf("%d:%d", c.DateModified.Unix(), c.ProductId)
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+// ParseProductCursor decodes a cursor returned by Encode; an empty value means the first page
+func ParseProductCursor(value string) (*ProductCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("cursor: %w", ErrInvalid)
+	}
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("cursor: %w", ErrInvalid)
+	}
+	seconds, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("cursor: %w", ErrInvalid)
+	}
+	productId, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("cursor: %w", ErrInvalid)
+	}
+	return &ProductCursor{
+		DateModified: time.Unix(seconds, 0).UTC(),
+		ProductId:    productId,
+	}, nil
+}
+
+// ProductChanges is a page of products modified since a given time
+type ProductChanges struct {
+	Products   []*ProductView `json:"products"`
+	NextCursor string         `json:"next_cursor,omitempty"` // empty on the last page
+}
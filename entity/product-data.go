@@ -3,6 +3,7 @@ package entity
 import (
 	"net/http"
 	"ocapi/internal/lib/validate"
+	"time"
 )
 
 type ProductData struct {
@@ -22,11 +23,26 @@ type ProductData struct {
 	Active        bool           `json:"active"`
 	Weight        float64        `json:"weight" validate:"min=0"`
 	WeightClassId int            `json:"weight_class_id" validate:"min=0"`
+	WeightClass   IdOrName       `json:"weight_class"`                      // weight class ID, unit code (kg) or title; overrides weight_class_id
+	Length        *float64       `json:"length" validate:"omitempty,min=0"` // dimensions are kept if omitted
+	Width         *float64       `json:"width" validate:"omitempty,min=0"`
+	Height        *float64       `json:"height" validate:"omitempty,min=0"`
+	LengthClass   IdOrName       `json:"length_class"` // length class ID, unit code (cm) or title
 	Categories    []string       `json:"categories"`
 	Attributes    []string       `json:"attributes"`
 	Images        []string       `json:"images"`
 	CustomFields  []*CustomField `json:"custom_fields" validate:"omitempty,dive"`
+	Stores        []int64        `json:"stores"`      // store IDs to publish the product to
+	StoreCodes    []string       `json:"store_codes"` // configured store codes, alternative to IDs
 	BatchUid      string         `json:"batch_uid"`
+	// overrides of the configured product defaults; empty values keep the default or the stored value
+	TaxClass       IdOrName   `json:"tax_class"`                // tax class ID or title
+	StockStatusIn  IdOrName   `json:"stock_status_in"`          // stock status ID or name used when quantity > 0
+	StockStatusOut IdOrName   `json:"stock_status_out"`         // stock status ID or name used when quantity is 0
+	Minimum        int        `json:"minimum" validate:"min=0"` // minimum order quantity
+	Subtract       *bool      `json:"subtract"`                 // subtract stock on order
+	Shipping       *bool      `json:"shipping"`                 // requires shipping
+	DateAvailable  *time.Time `json:"date_available"`
 }
 
 func (p *ProductData) Bind(_ *http.Request) error {
@@ -40,11 +56,12 @@ func (p *ProductData) Status() int {
 	return 0
 }
 
-func (p *ProductData) StockStatusID() int {
+// StockStatusID selects the stock status by quantity
+func (p *ProductData) StockStatusID(inStock, outOfStock int64) int64 {
 	if p.Quantity > 0 {
-		return 7
+		return inStock
 	}
-	return 5
+	return outOfStock
 }
 
 type ProductDataRequest struct {
@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"net/http"
+	"ocapi/internal/lib/validate"
+)
+
+type ProductDeleteRequest struct {
+	Data         []string `json:"data" validate:"required,min=1,dive,required"` // product UIDs
+	DeleteImages bool     `json:"delete_images"`                                // also remove image files no longer used
+}
+
+func (p *ProductDeleteRequest) Bind(_ *http.Request) error {
+	return validate.Struct(p)
+}
@@ -6,21 +6,64 @@ import (
 )
 
 type ProductDescription struct {
-	ProductUid        string `json:"product_uid" validate:"required"`
-	LanguageId        int64  `json:"language_id,omitempty" validate:"required"`
-	Name              string `json:"name,omitempty"`
-	Description       string `json:"description,omitempty"`
-	MetaTitle         string `json:"meta_title,omitempty"`
-	MetaKeyword       string `json:"meta_keyword,omitempty"`
-	MetaDescription   string `json:"meta_description,omitempty"`
-	SeoKeyword        string `json:"seo_keyword,omitempty"`
-	UpdateDescription bool   `json:"update_description,omitempty"`
+	ProductUid      string   `json:"product_uid" validate:"required"`
+	LanguageId      int64    `json:"language_id,omitempty" validate:"required_without=Language"`
+	Language        string   `json:"language,omitempty"`
+	Name            string   `json:"name,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	MetaTitle       string   `json:"meta_title,omitempty"`
+	MetaKeyword     string   `json:"meta_keyword,omitempty"`
+	MetaDescription string   `json:"meta_description,omitempty"`
+	Tag             string   `json:"tag,omitempty"`
+	SeoKeyword      string   `json:"seo_keyword,omitempty"`
+	Fields          []string `json:"fields,omitempty" validate:"omitempty,dive,oneof=name description meta_title meta_keyword meta_description tag"`
+	// Deprecated: list "description" in Fields instead
+	UpdateDescription bool `json:"update_description,omitempty"`
 }
 
 func (p *ProductDescription) Bind(_ *http.Request) error {
 	return validate.Struct(p)
 }
 
+// values returns all description columns with their values
+func (p *ProductDescription) values() map[string]interface{} {
+	return map[string]interface{}{
+		"name":             p.Name,
+		"description":      p.Description,
+		"meta_title":       p.MetaTitle,
+		"meta_keyword":     p.MetaKeyword,
+		"meta_description": p.MetaDescription,
+		"tag":              p.Tag,
+	}
+}
+
+// InsertFields returns the columns of a new description; meta title falls back to the name
+func (p *ProductDescription) InsertFields() map[string]interface{} {
+	fields := p.values()
+	if p.MetaTitle == "" {
+		fields["meta_title"] = p.Name
+	}
+	return fields
+}
+
+// UpdateFields returns the columns of an existing description listed in the fields mask.
+// Without a mask only the name is updated, plus a non-empty description if UpdateDescription is set.
+func (p *ProductDescription) UpdateFields() map[string]interface{} {
+	values := p.values()
+	fields := make(map[string]interface{})
+	if len(p.Fields) == 0 {
+		fields["name"] = p.Name
+		if p.UpdateDescription && p.Description != "" {
+			fields["description"] = p.Description
+		}
+		return fields
+	}
+	for _, field := range p.Fields {
+		fields[field] = values[field]
+	}
+	return fields
+}
+
 type ProductDescriptionRequest struct {
 	Data []*ProductDescription `json:"data" validate:"required,dive"`
 }
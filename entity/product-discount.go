@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"net/http"
+	"ocapi/internal/lib/validate"
+	"time"
+)
+
+type ProductDiscount struct {
+	ProductUid string    `json:"product_uid" validate:"required"`
+	GroupId    int64     `json:"group_id" validate:"required,number,gt=0"`
+	Quantity   int       `json:"quantity" validate:"required,number,gt=0"` // minimum quantity of the tier
+	Price      float64   `json:"price" validate:"required,number,gt=0"`
+	Priority   int       `json:"priority" validate:"omitempty,number"`
+	DateStart  time.Time `json:"date_start" validate:"omitempty"`
+	DateEnd    time.Time `json:"date_end" validate:"omitempty"`
+}
+
+// Overlaps reports whether both tiers apply to the same customer group and quantity
+// within intersecting date ranges.
+func (d *ProductDiscount) Overlaps(other *ProductDiscount) bool {
+	return d.GroupId == other.GroupId && d.Quantity == other.Quantity &&
+		datesOverlap(d.DateStart, d.DateEnd, other.DateStart, other.DateEnd)
+}
+
+type ProductDiscountRequest struct {
+	Data []*ProductDiscount `json:"data" validate:"required,dive"`
+}
+
+func (p *ProductDiscountRequest) Bind(_ *http.Request) error {
+	return validate.Struct(p)
+}
@@ -5,6 +5,8 @@ type ProductImageData struct {
 	FileUid    string `json:"file_uid"`
 	SortOrder  int    `json:"sort_order"`
 	ImageUrl   string `json:"image"`
+	FileHash   string `json:"file_hash"` // SHA-256 of the received file content
+	Version    string `json:"version"`   // image version assigned by the client
 	IsMain     bool   `json:"is_main"`
 }
 
@@ -20,6 +22,7 @@ func NewFromProductImage(productImage *ProductImage) *ProductImageData {
 		ProductUid: productImage.ProductUid,
 		FileUid:    productImage.FileUid,
 		IsMain:     productImage.IsMain,
+		Version:    productImage.Version,
 		SortOrder:  so,
 	}
 }
@@ -26,3 +26,42 @@ type ProductImageRequest struct {
 func (p *ProductImageRequest) Bind(_ *http.Request) error {
 	return validate.Struct(p)
 }
+
+// ProductImageUpload describes an image file sent as multipart/form-data
+type ProductImageUpload struct {
+	ProductUid string `json:"product_uid" validate:"required"`
+	FileUid    string `json:"file_uid" validate:"required"`
+	IsMain     bool   `json:"is_main"`
+	Version    string `json:"version"`
+	SortOrder  int64  `json:"sort_order" validate:"number"`
+}
+
+func (p *ProductImageUpload) Bind(_ *http.Request) error {
+	return validate.Struct(p)
+}
+
+// ImageVersion identifies a version of an image file the client holds
+type ImageVersion struct {
+	FileUid string `json:"file_uid" validate:"required"`
+	Version string `json:"version" validate:"required"`
+}
+
+type ImageCheckRequest struct {
+	Data []*ImageVersion `json:"data" validate:"required,dive"`
+}
+
+func (i *ImageCheckRequest) Bind(_ *http.Request) error {
+	return validate.Struct(i)
+}
+
+// ImageCheckResult lists images that have to be uploaded: missing in the shop or stored with another version
+type ImageCheckResult struct {
+	Missing []string `json:"missing"`
+	Stale   []string `json:"stale"`
+}
+
+// StoredImage is an image file stored for a product with its version
+type StoredImage struct {
+	Image   string
+	Version string
+}
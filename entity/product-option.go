@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"net/http"
+	"ocapi/internal/lib/validate"
+)
+
+type ProductOption struct {
+	ProductUid string                `json:"product_uid" validate:"required"`
+	OptionUid  string                `json:"option_uid" validate:"required"`
+	Required   bool                  `json:"required"`
+	Value      string                `json:"value"` // default value for text, date and file options
+	Values     []*ProductOptionValue `json:"values" validate:"omitempty,dive"`
+}
+
+type ProductOptionValue struct {
+	OptionValueUid string  `json:"option_value_uid" validate:"required"`
+	Quantity       int     `json:"quantity" validate:"min=0"`
+	Subtract       bool    `json:"subtract"`
+	Price          float64 `json:"price"`  // signed price modifier, negative values decrease the product price
+	Weight         float64 `json:"weight"` // signed weight modifier
+}
+
+// PricePrefix returns the OpenCart price prefix for the signed modifier
+func (v *ProductOptionValue) PricePrefix() string {
+	return modifierPrefix(v.Price)
+}
+
+// WeightPrefix returns the OpenCart weight prefix for the signed modifier
+func (v *ProductOptionValue) WeightPrefix() string {
+	return modifierPrefix(v.Weight)
+}
+
+func (v *ProductOptionValue) SubtractFlag() int {
+	if v.Subtract {
+		return 1
+	}
+	return 0
+}
+
+func modifierPrefix(value float64) string {
+	if value < 0 {
+		return "-"
+	}
+	return "+"
+}
+
+type ProductOptionRequest struct {
+	Data []*ProductOption `json:"data" validate:"required,dive"`
+}
+
+func (p *ProductOptionRequest) Bind(_ *http.Request) error {
+	return validate.Struct(p)
+}
@@ -15,6 +15,18 @@ type ProductSpecial struct {
 	DateEnd    time.Time `json:"date_end" validate:"omitempty"`
 }
 
+// Overlaps reports whether both specials apply to the same customer group with the same priority
+// within intersecting date ranges.
+func (p *ProductSpecial) Overlaps(other *ProductSpecial) bool {
+	return p.GroupId == other.GroupId && p.Priority == other.Priority &&
+		datesOverlap(p.DateStart, p.DateEnd, other.DateStart, other.DateEnd)
+}
+
+// SpecialPurgeResult reports the removal of expired special prices
+type SpecialPurgeResult struct {
+	Removed int64 `json:"removed"`
+}
+
 type ProductSpecialRequest struct {
 	Data []*ProductSpecial `json:"data" validate:"required,dive"`
 }
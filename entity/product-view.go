@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Sub-resources of a product that can be requested with ?include=
+const (
+	IncludeDescriptions = "descriptions"
+	IncludeCategories   = "categories"
+	IncludeAttributes   = "attributes"
+	IncludeImages       = "images"
+	IncludeSpecials     = "specials"
+	IncludeDiscounts    = "discounts"
+	IncludeCustomFields = "custom_fields"
+)
+
+var productIncludes = []string{
+	IncludeDescriptions,
+	IncludeCategories,
+	IncludeAttributes,
+	IncludeImages,
+	IncludeSpecials,
+	IncludeDiscounts,
+	IncludeCustomFields,
+}
+
+// ProductView is the stored state of a product with its sub-resources
+type ProductView struct {
+	ProductId       int64                 `json:"product_id"`
+	Uid             string                `json:"product_uid"`
+	Article         string                `json:"article"`
+	Price           float64               `json:"price"`
+	Quantity        int                   `json:"quantity"`
+	StockStatusId   int                   `json:"stock_status_id"`
+	Manufacturer    string                `json:"manufacturer"`
+	ManufacturerUid string                `json:"manufacturer_uid"`
+	Active          bool                  `json:"active"`
+	Weight          float64               `json:"weight"`
+	WeightClassId   int                   `json:"weight_class_id"`
+	Length          float64               `json:"length"`
+	Width           float64               `json:"width"`
+	Height          float64               `json:"height"`
+	LengthClassId   int                   `json:"length_class_id"`
+	Image           string                `json:"image"`
+	ImageUid        string                `json:"-"`
+	DateAdded       time.Time             `json:"date_added"`
+	DateModified    time.Time             `json:"date_modified"`
+	Descriptions    []*ProductDescription `json:"descriptions,omitempty"`
+	Categories      []string              `json:"categories,omitempty"`
+	Attributes      []*ProductAttribute   `json:"attributes,omitempty"`
+	Images          []*ProductImageView   `json:"images,omitempty"`
+	Specials        []*ProductSpecial     `json:"specials,omitempty"`
+	Discounts       []*ProductDiscount    `json:"discounts,omitempty"`
+	CustomFields    []*CustomField        `json:"custom_fields,omitempty"`
+}
+
+// ProductImageView is a stored product image; the main image comes from the product row
+type ProductImageView struct {
+	FileUid   string `json:"file_uid"`
+	Image     string `json:"image"`
+	SortOrder int    `json:"sort_order"`
+	IsMain    bool   `json:"is_main"`
+}
+
+// ParseProductInclude parses a comma-separated list of sub-resources; an empty value selects all of them
+func ParseProductInclude(value string) (map[string]bool, error) {
+	include := make(map[string]bool, len(productIncludes))
+	if strings.TrimSpace(value) == "" {
+		for _, name := range productIncludes {
+			include[name] = true
+		}
+		return include, nil
+	}
+
+	allowed := make(map[string]bool, len(productIncludes))
+	for _, name := range productIncludes {
+		allowed[name] = true
+	}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !allowed[name] {
+			return nil, fmt.Errorf("include %s: %w", name, ErrInvalid)
+		}
+		include[name] = true
+	}
+	return include, nil
+}
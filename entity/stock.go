@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"net/http"
+	"ocapi/internal/lib/validate"
+)
+
+// StockItem carries a quantity and, optionally, a price update of a product
+type StockItem struct {
+	ProductUid string   `json:"product_uid" validate:"required"`
+	Quantity   int      `json:"quantity" validate:"min=0"`
+	Price      *float64 `json:"price" validate:"omitempty,min=0"` // price is kept if omitted
+}
+
+type StockRequest struct {
+	Data []*StockItem `json:"data" validate:"required,dive"`
+}
+
+func (s *StockRequest) Bind(_ *http.Request) error {
+	return validate.Struct(s)
+}
+
+// StockResult reports how many products were updated and which UIDs are unknown
+type StockResult struct {
+	Updated     int      `json:"updated"`
+	Unknown     int      `json:"unknown"`
+	UnknownUids []string `json:"unknown_uids,omitempty"`
+}
@@ -5,24 +5,16 @@ import (
 	"ocapi/entity"
 )
 
-func (c *Core) LoadAttributes(attributes []*entity.Attribute) error {
+func (c *Core) LoadAttributes(attributes []*entity.Attribute, report bool) ([]*entity.ItemResult, error) {
 	if c.repo == nil {
-		return fmt.Errorf("repository not set")
+		return nil, fmt.Errorf("repository not set")
 	}
-	err := c.repo.SaveAttributes(attributes)
-	if err != nil {
-		return err
-	}
-	return nil
+	return c.repo.SaveAttributes(attributes, report)
 }
 
-func (c *Core) LoadProductAttributes(attributes []*entity.ProductAttribute) error {
+func (c *Core) LoadProductAttributes(attributes []*entity.ProductAttribute, report bool) ([]*entity.ItemResult, error) {
 	if c.repo == nil {
-		return fmt.Errorf("repository not set")
-	}
-	err := c.repo.SaveProductAttributes(attributes)
-	if err != nil {
-		return err
+		return nil, fmt.Errorf("repository not set")
 	}
-	return nil
+	return c.repo.SaveProductAttributes(attributes, report)
 }
@@ -2,27 +2,36 @@ package core
 
 import (
 	"fmt"
+	"log/slog"
 	"ocapi/entity"
 )
 
-func (c *Core) LoadCategories(categories []*entity.CategoryData) error {
+func (c *Core) LoadCategories(categories []*entity.CategoryData, report bool) ([]*entity.ItemResult, error) {
 	if c.repo == nil {
-		return fmt.Errorf("repository not initialized")
+		return nil, fmt.Errorf("repository not initialized")
 	}
-	err := c.repo.SaveCategories(categories)
-	if err != nil {
-		return err
+	return c.repo.SaveCategories(categories, report)
+}
+
+func (c *Core) LoadCategoryDescriptions(categories []*entity.CategoryDescriptionData, report bool) ([]*entity.ItemResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
 	}
-	return nil
+	return c.repo.SaveCategoriesDescription(categories, report)
 }
 
-func (c *Core) LoadCategoryDescriptions(categories []*entity.CategoryDescriptionData) error {
+// RepairCategories rebuilds the category paths OpenCart uses for breadcrumbs and the admin listing.
+func (c *Core) RepairCategories() (*entity.CategoryRepairResult, error) {
 	if c.repo == nil {
-		return fmt.Errorf("repository not initialized")
+		return nil, fmt.Errorf("repository not initialized")
 	}
-	err := c.repo.SaveCategoriesDescription(categories)
+	result, err := c.repo.RepairCategoryPaths()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	c.log.With(
+		slog.Int("categories", result.Categories),
+		slog.Int("skipped", len(result.Skipped)),
+	).Info("category paths repaired")
+	return result, nil
 }
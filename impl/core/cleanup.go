@@ -0,0 +1,172 @@
+package core
+
+import (
+	"fmt"
+	"log/slog"
+	"ocapi/entity"
+	"ocapi/internal/lib/sl"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultImagePattern matches the names of image files written by OCAPI: {file_uid}{ext} with a UUID file_uid
+const defaultImagePattern = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\.(jpg|png|gif)$`
+
+// quarantineDate is the layout of quarantine folders, one per cleanup day
+const quarantineDate = "2006-01-02"
+
+// imageCleanup configures the orphan image cleanup
+type imageCleanup struct {
+	pattern    *regexp.Regexp
+	quarantine string
+	retention  int // days
+}
+
+// SetImageCleanup sets the file name pattern of managed images (empty = UUID names), the quarantine
+// folder in the image store and the number of days quarantined files are kept
+func (c *Core) SetImageCleanup(pattern, quarantine string, retentionDays int) error {
+	if pattern == "" {
+		pattern = defaultImagePattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("image pattern: %v", err)
+	}
+	quarantine = strings.Trim(quarantine, "/")
+	if quarantine == "" {
+		return fmt.Errorf("image quarantine folder not set")
+	}
+	c.imageCleanup = imageCleanup{pattern: re, quarantine: quarantine, retention: retentionDays}
+	return nil
+}
+
+// managedImage reports whether a file in the image store was written by OCAPI: it lies in the root
+// of the store and its name matches the naming pattern
+func (c *Core) managedImage(file string) bool {
+	if c.imageCleanup.pattern == nil || strings.Contains(file, "/") {
+		return false
+	}
+	return c.imageCleanup.pattern.MatchString(file)
+}
+
+// CleanupImages finds image files not used by any product and moves them to the quarantine folder;
+// with dryRun the files are only listed. Only files in the root of the image store matching the naming
+// pattern are considered, so images of banners, manufacturers etc. in subfolders are never touched.
+// Quarantined files older than the retention period are deleted.
+func (c *Core) CleanupImages(dryRun bool) (*entity.ImageCleanupReport, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+	if c.images == nil || c.imageCleanup.pattern == nil {
+		return nil, fmt.Errorf("image store not initialized")
+	}
+
+	images, err := c.repo.GetAllImages()
+	if err != nil {
+		return nil, err
+	}
+	validImages := make(map[string]bool, len(images))
+	for _, image := range images {
+		validImages[path.Base(image)] = true
+	}
+
+	files, err := c.images.List()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.ImageCleanupReport{
+		DryRun:  dryRun,
+		Orphans: make([]string, 0),
+		Purged:  make([]string, 0),
+	}
+	today := time.Now().Format(quarantineDate)
+	for _, file := range files {
+		if quarantined, ok := c.quarantinedImage(file); ok {
+			if !c.expired(quarantined) {
+				report.Quarantined++
+				continue
+			}
+			if !dryRun {
+				if err = c.images.Delete(file); err != nil {
+					return nil, fmt.Errorf("purge %s: %v", file, err)
+				}
+				c.log.With(slog.String("image", file)).Info("quarantined file removed")
+			}
+			report.Purged = append(report.Purged, file)
+			continue
+		}
+
+		if !c.managedImage(file) {
+			report.Skipped++
+			continue
+		}
+		if validImages[file] {
+			continue
+		}
+
+		report.Orphans = append(report.Orphans, file)
+		if dryRun {
+			continue
+		}
+		target := path.Join(c.imageCleanup.quarantine, today, file)
+		if err = c.images.Move(file, target); err != nil {
+			c.log.Error("quarantine image", slog.String("image", file), sl.Err(err))
+			return nil, fmt.Errorf("quarantine %s: %v", file, err)
+		}
+		c.invalidateImageCache(file)
+		c.log.With(slog.String("image", file), slog.String("target", target)).Info("file quarantined")
+		report.Quarantined++
+	}
+
+	return report, nil
+}
+
+// QuarantinedImages lists the image files kept in quarantine
+func (c *Core) QuarantinedImages() ([]*entity.QuarantinedImage, error) {
+	if c.images == nil || c.imageCleanup.pattern == nil {
+		return nil, fmt.Errorf("image store not initialized")
+	}
+
+	files, err := c.images.List()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entity.QuarantinedImage, 0)
+	for _, file := range files {
+		if quarantined, ok := c.quarantinedImage(file); ok {
+			result = append(result, quarantined)
+		}
+	}
+	return result, nil
+}
+
+// quarantinedImage parses a path like {quarantine}/{date}/{file}; false if the file is not in quarantine
+func (c *Core) quarantinedImage(file string) (*entity.QuarantinedImage, bool) {
+	rest, ok := strings.CutPrefix(file, c.imageCleanup.quarantine+"/")
+	if !ok {
+		return nil, false
+	}
+	folder, name, ok := strings.Cut(rest, "/")
+	if !ok {
+		return nil, false
+	}
+	date, err := time.Parse(quarantineDate, folder)
+	if err != nil {
+		return nil, false
+	}
+	return &entity.QuarantinedImage{
+		File:          name,
+		Path:          file,
+		QuarantinedAt: folder,
+		PurgeAfter:    date.AddDate(0, 0, c.imageCleanup.retention).Format(quarantineDate),
+	}, true
+}
+
+// expired reports whether the retention period of a quarantined file is over
+func (c *Core) expired(image *entity.QuarantinedImage) bool {
+	return image.PurgeAfter <= time.Now().Format(quarantineDate)
+}
@@ -2,30 +2,49 @@ package core
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"ocapi/entity"
 	"ocapi/internal/lib/sl"
-	"os"
-	"path/filepath"
 	"sync"
 	"time"
 )
 
 type Repository interface {
-	ProductSearch(uid string) (interface{}, error)
-	SaveProducts(products []*entity.ProductData) error
-	SaveProductsDescription(productsDescData []*entity.ProductDescription) error
+	ProductSearch(uid string, include map[string]bool) (*entity.ProductView, error)
+	ProductChanges(since time.Time, cursor *entity.ProductCursor, limit int) ([]*entity.ProductView, error)
+	SaveProducts(products []*entity.ProductData, report bool) ([]*entity.ItemResult, error)
+	SaveProductsDescription(productsDescData []*entity.ProductDescription, report bool) ([]*entity.ItemResult, error)
 	UpdateProductImage(imageData *entity.ProductImageData) error
 	CleanUpProductImages(productUid string, images []string) (map[string]bool, error)
 	InsertProductImage(productUid string, fileUid string, imageUrl string, sortOrder int) error
-	GetProductMainImage(productUid string) (string, error)
-	SaveProductAttributes(attributes []*entity.ProductAttribute) error
-	SaveProductSpecial(products []*entity.ProductSpecial) error
-
-	SaveCategories(categoriesData []*entity.CategoryData) error
-	SaveCategoriesDescription(categoriesDescData []*entity.CategoryDescriptionData) error
-
-	SaveAttributes(attributes []*entity.Attribute) error
+	GetProductMainImageUid(productUid string) (string, error)
+	FindImageByHash(hash string) (string, error)
+	FindImageByUid(fileUid string) (string, error)
+	ImageFileUids(image string) ([]string, error)
+	RelinkImage(image, target, fileUid string) error
+	ProductImageVersion(productUid, fileUid string, isMain bool) (*entity.StoredImage, error)
+	ImageVersions(fileUids []string) (map[string][]*entity.StoredImage, error)
+	SaveProductAttributes(attributes []*entity.ProductAttribute, report bool) ([]*entity.ItemResult, error)
+	SaveProductSpecial(products []*entity.ProductSpecial, report bool) ([]*entity.ItemResult, error)
+	ReplaceProductSpecials(specials []*entity.ProductSpecial, report bool) ([]*entity.ItemResult, error)
+	PurgeExpiredSpecials() (int64, error)
+	SaveProductDiscounts(discounts []*entity.ProductDiscount, report bool) ([]*entity.ItemResult, error)
+	DeleteProduct(uid string) ([]string, error)
+	UpdateStock(items []*entity.StockItem) (*entity.StockResult, error)
+	SaveManufacturers(manufacturers []*entity.Manufacturer, report bool) ([]*entity.ItemResult, error)
+	MergeManufacturers(source, target entity.ManufacturerRef) (*entity.ManufacturerMergeResult, error)
+	SaveDownload(download *entity.DownloadData) (string, error)
+	SetProductDownloads(productUid string, downloadUids []string) error
+
+	SaveCategories(categoriesData []*entity.CategoryData, report bool) ([]*entity.ItemResult, error)
+	SaveCategoriesDescription(categoriesDescData []*entity.CategoryDescriptionData, report bool) ([]*entity.ItemResult, error)
+	RepairCategoryPaths() (*entity.CategoryRepairResult, error)
+
+	SaveAttributes(attributes []*entity.Attribute, report bool) ([]*entity.ItemResult, error)
+
+	SaveOptions(options []*entity.Option, report bool) ([]*entity.ItemResult, error)
+	SaveProductOptions(productOptions []*entity.ProductOption, report bool) ([]*entity.ItemResult, error)
 
 	OrderSearchId(orderId int64) (*entity.Order, error)
 	OrderSearchStatus(statusId int64, from time.Time) ([]int64, error)
@@ -35,6 +54,10 @@ type Repository interface {
 
 	UpdateCurrencyValue(currencyCode string, value float64) error
 
+	Languages() []*entity.Language
+	Dictionary(name string) ([]*entity.DictionaryItem, error)
+	ReloadDictionaries() error
+
 	ReadTable(table, filter string, limit int, plain bool) (interface{}, error)
 	Stats() string
 	CheckApiKey(key string) (string, error)
@@ -43,6 +66,17 @@ type Repository interface {
 	GetAllImages() ([]string, error)
 }
 
+// ImageStore keeps image files by name relative to the images directory
+type ImageStore interface {
+	Put(name string, src io.ReadSeeker) error
+	Exists(name string) (bool, error)
+	Find(base string) (string, error)
+	List() ([]string, error)
+	Match(prefix string) ([]string, error)
+	Delete(name string) error
+	Move(name, target string) error
+}
+
 type MessageService interface {
 	SendEventMessage(msg *entity.EventMessage) error
 }
@@ -57,20 +91,27 @@ type cachedToken struct {
 const tokenCacheTTL = time.Hour
 
 type Core struct {
-	repo      Repository
-	ms        MessageService
-	authKey   string
-	imagePath string
-	imageUrl  string
-	keys      map[string]cachedToken
-	keysMu    sync.RWMutex
-	log       *slog.Logger
+	repo           Repository
+	ms             MessageService
+	authKey        string
+	images         ImageStore
+	imageUrl       string
+	downloadPath   string
+	imageLimits    imageLimits
+	imageCleanup   imageCleanup
+	imageCache     imageCache
+	keys           map[string]cachedToken
+	keysMu         sync.RWMutex
+	dictionaries   map[string]*entity.Dictionary
+	dictionariesMu sync.RWMutex
+	log            *slog.Logger
 }
 
 func New(log *slog.Logger) *Core {
 	return &Core{
-		log:  log.With(sl.Module("core")),
-		keys: make(map[string]cachedToken),
+		log:          log.With(sl.Module("core")),
+		keys:         make(map[string]cachedToken),
+		dictionaries: make(map[string]*entity.Dictionary),
 	}
 }
 
@@ -82,11 +123,20 @@ func (c *Core) SetAuthKey(key string) {
 	c.authKey = key
 }
 
-func (c *Core) SetImageParameters(imagePath, imageUrl string) {
-	c.imagePath = imagePath
+func (c *Core) SetImageParameters(images ImageStore, imageUrl string) {
+	c.images = images
 	c.imageUrl = imageUrl
 }
 
+// SetImageLimits sets the maximum dimensions of stored images (0 = no limit) and the JPEG quality used on re-encoding
+func (c *Core) SetImageLimits(maxWidth, maxHeight, quality int) {
+	c.imageLimits = imageLimits{maxWidth: maxWidth, maxHeight: maxHeight, quality: quality}
+}
+
+func (c *Core) SetDownloadPath(path string) {
+	c.downloadPath = path
+}
+
 func (c *Core) SetMessageService(ms MessageService) {
 	c.ms = ms
 }
@@ -115,58 +165,9 @@ func (c *Core) FinishBatch(batchUid string) (*entity.BatchResult, error) {
 	}
 	result := entity.NewBatchResult(batchUid, nil)
 	result.Products = productCount
-	count, err := c.checkImageFiles()
-	if err != nil {
-		c.log.Warn("check files", sl.Err(err))
-	}
-	result.DeletedFiles = count
 	return result, nil
 }
 
-func (c *Core) checkImageFiles() (int, error) {
-	if c.repo == nil {
-		return 0, fmt.Errorf("repository not set")
-	}
-
-	images, err := c.repo.GetAllImages()
-	if err != nil {
-		return 0, err
-	}
-	//extract file names from a path
-	validImages := make(map[string]bool)
-	for _, image := range images {
-		validImages[filepath.Base(image)] = true
-	}
-
-	var count int
-	err = filepath.Walk(c.imagePath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if info.IsDir() {
-			return nil
-		}
-
-		// Check if the current file is in the validImages list
-		file := filepath.Base(path)
-		if validImages[file] {
-			return nil
-		}
-
-		// If the file is not in the validImages list, delete it
-		if err = os.Remove(path); err != nil {
-			c.log.Error("removing image", sl.Err(err))
-			return err
-		}
-		c.log.With(slog.String("image", path)).Info("file removed")
-		count++
-
-		return nil
-	})
-
-	return count, err
-}
-
 func (c *Core) UpdateRates(data []*entity.Currency) error {
 	if c.repo == nil {
 		return fmt.Errorf("repository not set")
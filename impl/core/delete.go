@@ -0,0 +1,52 @@
+package core
+
+import (
+	"fmt"
+	"log/slog"
+	"ocapi/entity"
+	"ocapi/internal/lib/sl"
+	"strings"
+)
+
+// DeleteProducts removes products by UID with all related data. With deleteImages the image
+// files no longer used by other products are removed from the image store as well.
+// Without report the first failing product stops the request.
+func (c *Core) DeleteProducts(uids []string, deleteImages, report bool) ([]*entity.ItemResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+
+	results := make([]*entity.ItemResult, 0, len(uids))
+	for _, uid := range uids {
+		images, err := c.repo.DeleteProduct(uid)
+		if err == nil && deleteImages && c.images != nil {
+			c.removeImageFiles(uid, images)
+		}
+		if err != nil && !report {
+			return nil, err
+		}
+		results = append(results, entity.NewItemResult(uid, err))
+	}
+	return results, nil
+}
+
+// removeImageFiles deletes image files of a removed product; failures are logged only,
+// as the product itself is already deleted. Only files written by OCAPI are removed: those under
+// the image URL directly in the images folder and matching the cleanup naming pattern; images added
+// in the OpenCart admin are kept.
+func (c *Core) removeImageFiles(uid string, images []string) {
+	for _, image := range images {
+		file, ok := strings.CutPrefix(image, c.imageUrl)
+		if !ok || !c.managedImage(file) {
+			c.log.With(slog.String("product_uid", uid), slog.String("image", image)).Debug("image file kept")
+			continue
+		}
+		logger := c.log.With(slog.String("product_uid", uid), slog.String("image", file))
+		if err := c.images.Delete(file); err != nil {
+			logger.Error("removing image", sl.Err(err))
+			continue
+		}
+		c.invalidateImageCache(file)
+		logger.Debug("image file removed")
+	}
+}
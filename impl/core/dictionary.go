@@ -0,0 +1,52 @@
+package core
+
+import (
+	"fmt"
+	"log/slog"
+	"ocapi/entity"
+	"time"
+)
+
+// Dictionary returns the reference list with the given name. Lists are read from the database
+// on first use and kept in memory until RefreshDictionaries.
+func (c *Core) Dictionary(name string) (*entity.Dictionary, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+
+	c.dictionariesMu.RLock()
+	dictionary, ok := c.dictionaries[name]
+	c.dictionariesMu.RUnlock()
+	if ok {
+		return dictionary, nil
+	}
+
+	items, err := c.repo.Dictionary(name)
+	if err != nil {
+		return nil, err
+	}
+	dictionary = entity.NewDictionary(name, items, time.Now())
+
+	c.dictionariesMu.Lock()
+	c.dictionaries[name] = dictionary
+	c.dictionariesMu.Unlock()
+	c.log.With(slog.String("dictionary", name), slog.Int("size", len(items))).Debug("dictionary loaded")
+	return dictionary, nil
+}
+
+// RefreshDictionaries drops the cached reference lists and reloads the languages and IDs
+// the repository resolves by name, to pick up changes made in the OpenCart admin.
+func (c *Core) RefreshDictionaries() error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not initialized")
+	}
+	if err := c.repo.ReloadDictionaries(); err != nil {
+		return err
+	}
+
+	c.dictionariesMu.Lock()
+	c.dictionaries = make(map[string]*entity.Dictionary)
+	c.dictionariesMu.Unlock()
+	c.log.Info("dictionaries refreshed")
+	return nil
+}
@@ -0,0 +1,13 @@
+package core
+
+import (
+	"fmt"
+	"ocapi/entity"
+)
+
+func (c *Core) LoadProductDiscounts(discounts []*entity.ProductDiscount, report bool) ([]*entity.ItemResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+	return c.repo.SaveProductDiscounts(discounts, report)
+}
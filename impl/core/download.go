@@ -0,0 +1,90 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"ocapi/entity"
+	"ocapi/internal/lib/sl"
+	"os"
+	"path/filepath"
+)
+
+// LoadDownloads saves download files to the downloads directory and registers them in the shop.
+// Files are stored under OpenCart's masked name: the original name followed by a random token,
+// so they cannot be guessed; a replaced file is removed after the database is updated.
+func (c *Core) LoadDownloads(downloads []*entity.Download) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not initialized")
+	}
+	if c.downloadPath == "" {
+		return fmt.Errorf("download path not set")
+	}
+
+	for _, download := range downloads {
+		fileData, err := base64.StdEncoding.DecodeString(download.FileData)
+		if err != nil {
+			return fmt.Errorf("decode base64 %s: %v", download.DownloadUid, err)
+		}
+
+		mask := filepath.Base(download.FileName)
+		token, err := randomToken()
+		if err != nil {
+			return fmt.Errorf("download %s: %v", download.DownloadUid, err)
+		}
+		filename := fmt.Sprintf("%s.%s", mask, token)
+
+		err = os.WriteFile(filepath.Join(c.downloadPath, filename), fileData, 0644)
+		if err != nil {
+			return fmt.Errorf("save download %s: %v", download.DownloadUid, err)
+		}
+
+		logger := c.log.With(
+			slog.String("download_uid", download.DownloadUid),
+			slog.String("filename", filename),
+		)
+
+		previous, err := c.repo.SaveDownload(&entity.DownloadData{
+			DownloadUid:  download.DownloadUid,
+			Filename:     filename,
+			Mask:         mask,
+			Descriptions: download.Descriptions,
+		})
+		if err != nil {
+			logger.Error("save download", sl.Err(err))
+			_ = os.Remove(filepath.Join(c.downloadPath, filename))
+			return fmt.Errorf("download %s: %v", download.DownloadUid, err)
+		}
+
+		if previous != "" && previous != filename {
+			if err = os.Remove(filepath.Join(c.downloadPath, filepath.Base(previous))); err != nil && !os.IsNotExist(err) {
+				logger.Warn("remove replaced download", sl.Err(err))
+			}
+		}
+		logger.Debug("download loaded")
+	}
+	return nil
+}
+
+func (c *Core) SetProductDownloads(products []*entity.ProductDownload) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not initialized")
+	}
+	for _, product := range products {
+		if err := c.repo.SetProductDownloads(product.ProductUid, product.DownloadUids); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// randomToken returns 32 random hex characters, as OpenCart appends to download file names.
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"ocapi/internal/lib/imaging"
+	"ocapi/internal/lib/sl"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// imageCache maintains resized copies OpenCart keeps in its image cache
+type imageCache struct {
+	store ImageStore
+	sizes []thumbnailSize
+}
+
+type thumbnailSize struct {
+	width  int
+	height int
+}
+
+// SetImageCache sets the store of the OpenCart image cache and the thumbnail sizes, like 228x228,
+// generated for new images; a nil store disables the cache maintenance
+func (c *Core) SetImageCache(store ImageStore, sizes []string) error {
+	cache := imageCache{store: store}
+	for _, size := range sizes {
+		w, h, ok := strings.Cut(strings.ToLower(size), "x")
+		width, errW := strconv.Atoi(w)
+		height, errH := strconv.Atoi(h)
+		if !ok || errW != nil || errH != nil || width <= 0 || height <= 0 {
+			return fmt.Errorf("invalid thumbnail size: %s", size)
+		}
+		cache.sizes = append(cache.sizes, thumbnailSize{width: width, height: height})
+	}
+	c.imageCache = cache
+	return nil
+}
+
+// cachePath returns the name OpenCart gives a resized copy of the image: the image path relative
+// to the images directory with -{width}x{height} before the extension
+func (c *Core) cachePath(fileName string, size thumbnailSize) string {
+	image := c.imageUrl + fileName
+	ext := path.Ext(image)
+	return fmt.Sprintf("%s-%dx%d%s", strings.TrimSuffix(image, ext), size.width, size.height, ext)
+}
+
+// invalidateImageCache deletes all cached resized copies of an image file, so the storefront does
+// not show the replaced or removed image; failures are logged only
+func (c *Core) invalidateImageCache(fileName string) {
+	if c.imageCache.store == nil {
+		return
+	}
+	image := c.imageUrl + fileName
+	base := strings.TrimSuffix(path.Base(image), path.Ext(image))
+	variant := regexp.MustCompile(`^` + regexp.QuoteMeta(base) + `-\d+x\d+\.[A-Za-z0-9]+$`)
+
+	logger := c.log.With(slog.String("image", image))
+	names, err := c.imageCache.store.Match(path.Join(path.Dir(image), base+"-"))
+	if err != nil {
+		logger.Warn("image cache lookup", sl.Err(err))
+		return
+	}
+	for _, name := range names {
+		if !variant.MatchString(path.Base(name)) {
+			continue
+		}
+		if err = c.imageCache.store.Delete(name); err != nil {
+			logger.Warn("image cache delete", slog.String("file", name), sl.Err(err))
+			continue
+		}
+		logger.With(slog.String("file", name)).Debug("cached image removed")
+	}
+}
+
+// refreshImageCache replaces cached copies of a newly written image: the old ones are deleted and
+// thumbnails of the configured sizes are generated from the stored content
+func (c *Core) refreshImageCache(fileName string, src io.ReadSeeker, format string) {
+	if c.imageCache.store == nil {
+		return
+	}
+	c.invalidateImageCache(fileName)
+
+	for _, size := range c.imageCache.sizes {
+		name := c.cachePath(fileName, size)
+		logger := c.log.With(slog.String("file", name))
+
+		if _, err := src.Seek(0, io.SeekStart); err != nil {
+			logger.Warn("thumbnail", sl.Err(err))
+			return
+		}
+		img, err := imaging.Thumbnail(src, format, size.width, size.height)
+		if err != nil {
+			logger.Warn("thumbnail", sl.Err(err))
+			return
+		}
+		var buf bytes.Buffer
+		if err = imaging.Encode(&buf, img, format, c.imageLimits.quality); err != nil {
+			logger.Warn("thumbnail encode", sl.Err(err))
+			return
+		}
+		if err = c.imageCache.store.Put(name, bytes.NewReader(buf.Bytes())); err != nil {
+			logger.Warn("thumbnail save", sl.Err(err))
+			return
+		}
+		logger.Debug("thumbnail generated")
+	}
+}
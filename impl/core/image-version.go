@@ -0,0 +1,82 @@
+package core
+
+import (
+	"fmt"
+	"ocapi/entity"
+	"ocapi/internal/lib/sl"
+	"path"
+)
+
+// imageIsCurrent reports whether the product already has the image with the same version and its file
+// is present, so the upload can be skipped. Images without a version are always written.
+func (c *Core) imageIsCurrent(image *entity.ProductImage) bool {
+	if image.Version == "" || c.images == nil {
+		return false
+	}
+	stored, err := c.repo.ProductImageVersion(image.ProductUid, image.FileUid, image.IsMain)
+	if err != nil {
+		c.log.Warn("image version", sl.Err(err))
+		return false
+	}
+	return stored.Version == image.Version && c.imageFileExists(stored.Image)
+}
+
+// CheckImages compares image versions held by the client with the stored ones and returns the images
+// to be uploaded: missing ones (not stored or file absent) and stale ones (stored with another version).
+func (c *Core) CheckImages(images []*entity.ImageVersion) (*entity.ImageCheckResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+	if c.images == nil {
+		return nil, fmt.Errorf("image store not initialized")
+	}
+
+	uids := make([]string, 0, len(images))
+	for _, image := range images {
+		uids = append(uids, image.FileUid)
+	}
+	versions, err := c.repo.ImageVersions(uids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.ImageCheckResult{
+		Missing: make([]string, 0),
+		Stale:   make([]string, 0),
+	}
+	for _, image := range images {
+		stored := versions[image.FileUid]
+		if len(stored) == 0 {
+			result.Missing = append(result.Missing, image.FileUid)
+			continue
+		}
+		missing, stale := false, false
+		for _, s := range stored {
+			if !c.imageFileExists(s.Image) {
+				missing = true
+			}
+			if s.Version != image.Version {
+				stale = true
+			}
+		}
+		switch {
+		case missing:
+			result.Missing = append(result.Missing, image.FileUid)
+		case stale:
+			result.Stale = append(result.Stale, image.FileUid)
+		}
+	}
+	return result, nil
+}
+
+// imageFileExists reports whether the file of a stored image path is present in the image store.
+func (c *Core) imageFileExists(image string) bool {
+	if image == "" {
+		return false
+	}
+	exists, err := c.images.Exists(path.Base(image))
+	if err != nil {
+		c.log.Warn("image file lookup", sl.Err(err))
+	}
+	return exists
+}
@@ -0,0 +1,14 @@
+package core
+
+import (
+	"fmt"
+	"ocapi/entity"
+)
+
+// Languages returns the shop languages with their codes accepted instead of language IDs.
+func (c *Core) Languages() ([]*entity.Language, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+	return c.repo.Languages(), nil
+}
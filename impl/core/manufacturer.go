@@ -0,0 +1,30 @@
+package core
+
+import (
+	"fmt"
+	"log/slog"
+	"ocapi/entity"
+)
+
+func (c *Core) LoadManufacturers(manufacturers []*entity.Manufacturer, report bool) ([]*entity.ItemResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+	return c.repo.SaveManufacturers(manufacturers, report)
+}
+
+// MergeManufacturers moves the products of a duplicate manufacturer to another one and deletes the duplicate.
+func (c *Core) MergeManufacturers(merge *entity.ManufacturerMerge) (*entity.ManufacturerMergeResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+	result, err := c.repo.MergeManufacturers(merge.Source, merge.Target)
+	if err != nil {
+		return nil, err
+	}
+	c.log.With(
+		slog.Int64("manufacturer_id", result.ManufacturerId),
+		slog.Int64("products", result.Products),
+	).Info("manufacturers merged")
+	return result, nil
+}
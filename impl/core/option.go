@@ -0,0 +1,20 @@
+package core
+
+import (
+	"fmt"
+	"ocapi/entity"
+)
+
+func (c *Core) LoadOptions(options []*entity.Option, report bool) ([]*entity.ItemResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not set")
+	}
+	return c.repo.SaveOptions(options, report)
+}
+
+func (c *Core) LoadProductOptions(options []*entity.ProductOption, report bool) ([]*entity.ItemResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not set")
+	}
+	return c.repo.SaveProductOptions(options, report)
+}
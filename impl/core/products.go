@@ -1,43 +1,34 @@
 package core
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
 	"log/slog"
 	"ocapi/entity"
 	"ocapi/internal/lib/sl"
-	"os"
-	"path/filepath"
-	"strings"
+	"time"
 )
 
-func (c *Core) FindProduct(uid string) (interface{}, error) {
+func (c *Core) FindProduct(uid string, include map[string]bool) (*entity.ProductView, error) {
 	if c.repo == nil {
 		return nil, fmt.Errorf("repository not initialized")
 	}
-	return c.repo.ProductSearch(uid)
+	return c.repo.ProductSearch(uid, include)
 }
 
-func (c *Core) LoadProducts(products []*entity.ProductData) error {
+func (c *Core) LoadProducts(products []*entity.ProductData, report bool) ([]*entity.ItemResult, error) {
 	if c.repo == nil {
-		return fmt.Errorf("repository not initialized")
-	}
-	err := c.repo.SaveProducts(products)
-	if err != nil {
-		return err
+		return nil, fmt.Errorf("repository not initialized")
 	}
-	return nil
+	return c.repo.SaveProducts(products, report)
 }
 
-func (c *Core) LoadProductDescriptions(products []*entity.ProductDescription) error {
+func (c *Core) LoadProductDescriptions(products []*entity.ProductDescription, report bool) ([]*entity.ItemResult, error) {
 	if c.repo == nil {
-		return fmt.Errorf("repository not initialized")
-	}
-	err := c.repo.SaveProductsDescription(products)
-	if err != nil {
-		return err
+		return nil, fmt.Errorf("repository not initialized")
 	}
-	return nil
+	return c.repo.SaveProductsDescription(products, report)
 }
 
 func (c *Core) LoadProductImages(products []*entity.ProductImage) error {
@@ -46,40 +37,55 @@ func (c *Core) LoadProductImages(products []*entity.ProductImage) error {
 	}
 
 	for _, product := range products {
+		if c.imageIsCurrent(product) {
+			c.log.With(
+				slog.String("product_uid", product.ProductUid),
+				slog.String("file_uid", product.FileUid),
+				slog.String("version", product.Version),
+			).Debug("image version unchanged")
+			continue
+		}
+
 		// Decode base64 image data
 		fileData, err := base64.StdEncoding.DecodeString(product.FileData)
 		if err != nil {
 			return fmt.Errorf("decode base64 %s: %v", product.ProductUid, err)
 		}
 
-		fileName := fmt.Sprintf("%s%s", product.FileUid, product.FileExt)
-		imagePath := filepath.Join(c.imagePath, fileName)
-
-		// Save image file
-		err = os.WriteFile(imagePath, fileData, 0644)
+		// Save image file; the extension follows the actual image format
+		fileName, hash, err := c.storeImage(bytes.NewReader(fileData), product.FileUid)
 		if err != nil {
-			return fmt.Errorf("save image %s: %v", product.ProductUid, err)
+			return fmt.Errorf("save image %s: %w", product.ProductUid, err)
 		}
 
-		imageUrl := fmt.Sprintf("%s%s%s", c.imageUrl, product.FileUid, product.FileExt)
+		if err = c.updateProductImage(product, fileName, hash); err != nil {
+			return err
+		}
+	}
 
-		imageData := entity.NewFromProductImage(product)
-		imageData.ImageUrl = imageUrl
+	return nil
+}
 
-		logger := c.log.With(
-			slog.String("product_uid", product.ProductUid),
-			slog.String("image_url", imageUrl),
-			slog.Bool("is_main", product.IsMain),
-		)
+// updateProductImage links a saved image file to the product.
+func (c *Core) updateProductImage(product *entity.ProductImage, fileName, hash string) error {
+	imageUrl := c.imageUrl + fileName
 
-		err = c.repo.UpdateProductImage(imageData)
-		if err != nil {
-			logger.Error("update product image", sl.Err(err))
-			return fmt.Errorf("product %s: %v", product.ProductUid, err)
-		}
-		logger.Debug("image loaded")
-	}
+	imageData := entity.NewFromProductImage(product)
+	imageData.ImageUrl = imageUrl
+	imageData.FileHash = hash
+
+	logger := c.log.With(
+		slog.String("product_uid", product.ProductUid),
+		slog.String("image_url", imageUrl),
+		slog.Bool("is_main", product.IsMain),
+	)
 
+	err := c.repo.UpdateProductImage(imageData)
+	if err != nil {
+		logger.Error("update product image", sl.Err(err))
+		return fmt.Errorf("product %s: %v", product.ProductUid, err)
+	}
+	logger.Debug("image loaded")
 	return nil
 }
 
@@ -112,7 +118,7 @@ func (c *Core) SetProductImages(products []*entity.ProductData) error {
 				continue
 			}
 
-			// Resolve image file on disk by mask {imagePath}/{fileUid}*
+			// Resolve image file in the store by mask {fileUid}.*
 			imageUrl, err := c.resolveImageUrl(fileUid)
 			if err != nil {
 				return fmt.Errorf("product %s, image %s: %v", product.Uid, fileUid, err)
@@ -127,44 +133,91 @@ func (c *Core) SetProductImages(products []*entity.ProductData) error {
 	return nil
 }
 
-// mainImageUid extracts the file UID from the product's main image path.
+// mainImageUid returns the file UID of the product's main image.
 // Returns empty string if the main image is not set or on error.
 func (c *Core) mainImageUid(productUid string) string {
-	mainImage, err := c.repo.GetProductMainImage(productUid)
-	if err != nil || mainImage == "" {
+	uid, err := c.repo.GetProductMainImageUid(productUid)
+	if err != nil {
 		return ""
 	}
-	// Extract UID from path like "catalog/products/798b00f4-d767-11f0-9d8e-0cc47a39a0b2.jpg"
-	base := filepath.Base(mainImage)
-	ext := filepath.Ext(base)
-	return strings.TrimSuffix(base, ext)
+	return uid
 }
 
-// resolveImageUrl finds the image file on disk by file_uid glob pattern
+// resolveImageUrl finds the image file in the store by file_uid with any extension
 // and returns the relative URL for the database (e.g. "catalog/product/uid.jpg").
+// An image stored in the file of another UID with the same content is found by its stored path.
 func (c *Core) resolveImageUrl(fileUid string) (string, error) {
-	pattern := filepath.Join(c.imagePath, fileUid+".*")
-	matches, err := filepath.Glob(pattern)
+	if c.images == nil {
+		return "", fmt.Errorf("image store not initialized")
+	}
+	fileName, err := c.images.Find(fileUid)
 	if err != nil {
-		return "", fmt.Errorf("glob %s: %v", pattern, err)
+		return "", fmt.Errorf("find %s: %v", fileUid, err)
 	}
-	if len(matches) == 0 {
-		return "", fmt.Errorf("image file not found: %s", pattern)
+	if fileName == "" {
+		if image, err := c.repo.FindImageByUid(fileUid); err == nil && image != "" {
+			return image, nil
+		}
+		return "", fmt.Errorf("image file not found: %s.*", fileUid)
 	}
 
-	// Use the first match; extract the file extension
-	fileName := filepath.Base(matches[0])
 	imageUrl := c.imageUrl + fileName
 	return imageUrl, nil
 }
 
-func (c *Core) LoadProductSpecial(products []*entity.ProductSpecial) error {
+// LoadProductSpecial saves special prices; with replace the request is the complete set of specials
+// of every product it mentions, otherwise one special per product and customer group is upserted.
+func (c *Core) LoadProductSpecial(products []*entity.ProductSpecial, replace, report bool) ([]*entity.ItemResult, error) {
 	if c.repo == nil {
-		return fmt.Errorf("repository not initialized")
+		return nil, fmt.Errorf("repository not initialized")
+	}
+	if replace {
+		return c.repo.ReplaceProductSpecials(products, report)
+	}
+	return c.repo.SaveProductSpecial(products, report)
+}
+
+// PurgeExpiredSpecials removes special prices whose end date has passed.
+func (c *Core) PurgeExpiredSpecials() (*entity.SpecialPurgeResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
 	}
-	err := c.repo.SaveProductSpecial(products)
+	removed, err := c.repo.PurgeExpiredSpecials()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	c.log.With(slog.Int64("removed", removed)).Info("expired specials purged")
+	return &entity.SpecialPurgeResult{Removed: removed}, nil
+}
+
+const (
+	defaultChangesLimit = 100
+	maxChangesLimit     = 1000
+)
+
+// ProductChanges returns a page of products modified since the given time, continuing after the cursor.
+// NextCursor is set only when more products follow.
+func (c *Core) ProductChanges(since time.Time, cursor *entity.ProductCursor, limit int) (*entity.ProductChanges, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+	if limit <= 0 {
+		limit = defaultChangesLimit
+	}
+	limit = min(limit, maxChangesLimit)
+
+	// one extra row tells whether there is a next page
+	products, err := c.repo.ProductChanges(since, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &entity.ProductChanges{Products: products}
+	if len(products) > limit {
+		changes.Products = products[:limit]
+		last := changes.Products[limit-1]
+		next := entity.ProductCursor{DateModified: last.DateModified, ProductId: last.ProductId}
+		changes.NextCursor = next.Encode()
+	}
+	return changes, nil
 }
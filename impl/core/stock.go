@@ -0,0 +1,13 @@
+package core
+
+import (
+	"fmt"
+	"ocapi/entity"
+)
+
+func (c *Core) UpdateStock(items []*entity.StockItem) (*entity.StockResult, error) {
+	if c.repo == nil {
+		return nil, fmt.Errorf("repository not initialized")
+	}
+	return c.repo.UpdateStock(items)
+}
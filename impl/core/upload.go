@@ -0,0 +1,192 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"ocapi/entity"
+	"ocapi/internal/lib/imaging"
+	"ocapi/internal/lib/sl"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// imageLimits configures normalization of stored images
+type imageLimits struct {
+	maxWidth  int
+	maxHeight int
+	quality   int
+}
+
+// UploadProductImage streams an image file into the image store and links it to the product.
+// The file is not read if the product already has the image with the same version.
+func (c *Core) UploadProductImage(image *entity.ProductImageUpload, file io.Reader) error {
+	if c.repo == nil {
+		return fmt.Errorf("repository not initialized")
+	}
+
+	product := &entity.ProductImage{
+		ProductUid: image.ProductUid,
+		FileUid:    image.FileUid,
+		IsMain:     image.IsMain,
+		Version:    image.Version,
+		SortOrder:  image.SortOrder,
+	}
+	if c.imageIsCurrent(product) {
+		c.log.With(
+			slog.String("product_uid", product.ProductUid),
+			slog.String("file_uid", product.FileUid),
+			slog.String("version", product.Version),
+		).Debug("image version unchanged")
+		return nil
+	}
+
+	fileName, hash, err := c.storeImage(file, image.FileUid)
+	if err != nil {
+		return fmt.Errorf("save image %s: %w", image.FileUid, err)
+	}
+
+	return c.updateProductImage(product, fileName, hash)
+}
+
+// storeImage saves image content as {fileUid}{ext} in the image store and returns the file name
+// and the SHA-256 hash of the received content. The content is streamed to a temporary file, checked
+// to be a JPEG, PNG or GIF image, rotated by its EXIF orientation and downscaled to the configured
+// limits if needed, then put to the store; the extension follows the actual format, and cached resized
+// copies of a replaced file are renewed. If an image with the same hash is already stored, also under
+// another file UID, its file is reused and nothing is written.
+func (c *Core) storeImage(src io.Reader, fileUid string) (string, string, error) {
+	if c.images == nil {
+		return "", "", fmt.Errorf("image store not initialized")
+	}
+
+	tmp, err := os.CreateTemp("", "ocapi-image-*")
+	if err != nil {
+		return "", "", fmt.Errorf("create temp file: %v", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hasher := sha256.New()
+	if _, err = io.Copy(tmp, io.TeeReader(src, hasher)); err != nil {
+		return "", "", fmt.Errorf("write: %v", err)
+	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	if stored := c.storedImage(hash); stored != "" {
+		return stored, hash, nil
+	}
+
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return "", "", err
+	}
+	info, err := imaging.Inspect(tmp)
+	if err != nil {
+		return "", "", fmt.Errorf("%v: %w", err, entity.ErrInvalid)
+	}
+
+	if info.NeedsNormalize(c.imageLimits.maxWidth, c.imageLimits.maxHeight) {
+		if err = c.normalizeImage(tmp, info); err != nil {
+			return "", "", err
+		}
+	}
+
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return "", "", err
+	}
+	fileName := path.Base(fileUid) + imaging.Extensions[info.Format]
+	if err = c.detachSharedImage(fileUid, fileName); err != nil {
+		return "", "", err
+	}
+	if err = c.images.Put(fileName, tmp); err != nil {
+		return "", "", err
+	}
+	c.refreshImageCache(fileName, tmp, info.Format)
+	return fileName, hash, nil
+}
+
+// normalizeImage rewrites the file with the oriented and downscaled image.
+func (c *Core) normalizeImage(file *os.File, info imaging.Info) error {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	img, err := imaging.Normalize(file, info, c.imageLimits.maxWidth, c.imageLimits.maxHeight)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, entity.ErrInvalid)
+	}
+
+	if err = file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err = imaging.Encode(file, img, info.Format, c.imageLimits.quality); err != nil {
+		return fmt.Errorf("encode: %v", err)
+	}
+	return nil
+}
+
+// storedImage returns the file name of an already stored image with the given content hash,
+// empty if there is none or its file is missing.
+func (c *Core) storedImage(hash string) string {
+	image, err := c.repo.FindImageByHash(hash)
+	if err != nil {
+		c.log.Warn("find image by hash", sl.Err(err))
+		return ""
+	}
+	if image == "" {
+		return ""
+	}
+	fileName := path.Base(image)
+	if !c.imageFileExists(fileName) {
+		return ""
+	}
+	return fileName
+}
+
+// detachSharedImage prepares the file of the file UID to be replaced. Images of other file UIDs with the
+// same content may be stored in it: the file is moved to the name of one of those UIDs and their images
+// are linked to the new path, so that replacing this image leaves theirs unchanged.
+func (c *Core) detachSharedImage(fileUid, fileName string) error {
+	image := c.imageUrl + fileName
+	uids, err := c.repo.ImageFileUids(image)
+	if err != nil {
+		return fmt.Errorf("image %s: %v", fileName, err)
+	}
+	uids = slices.DeleteFunc(uids, func(uid string) bool { return uid == fileUid })
+	if len(uids) == 0 {
+		return nil
+	}
+
+	for _, uid := range uids {
+		// the name must not be used by stored images, as they would change with the moved file
+		target := path.Base(uid) + path.Ext(fileName)
+		users, err := c.repo.ImageFileUids(c.imageUrl + target)
+		if err != nil {
+			return fmt.Errorf("image %s: %v", target, err)
+		}
+		if len(users) > 0 {
+			continue
+		}
+
+		if err = c.images.Move(fileName, target); err != nil {
+			return fmt.Errorf("move shared image %s: %v", fileName, err)
+		}
+		if err = c.repo.RelinkImage(image, c.imageUrl+target, fileUid); err != nil {
+			_ = c.images.Move(target, fileName)
+			return fmt.Errorf("relink shared image %s: %v", fileName, err)
+		}
+		// copies cached for a file removed earlier under the target name would be shown for the moved one
+		c.invalidateImageCache(target)
+		c.log.With(slog.String("image", fileName), slog.String("target", target)).Debug("shared image moved")
+		return nil
+	}
+	return fmt.Errorf("image %s is shared with %s and cannot be moved", fileName, strings.Join(uids, ", "))
+}
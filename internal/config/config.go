@@ -5,6 +5,7 @@ import (
 	"github.com/ilyakaznacheev/cleanenv"
 	"log"
 	"sync"
+	"time"
 )
 
 type Config struct {
@@ -25,12 +26,58 @@ type Config struct {
 		Prefix   string `yaml:"prefix" env-default:""`
 	} `yaml:"sql"`
 	Images struct {
-		Path string `yaml:"path" env-default:""`
-		Url  string `yaml:"url" env-default:""`
+		Storage string `yaml:"storage" env-default:"local"` // image storage: local or s3
+		Path    string `yaml:"path" env-default:""`
+		Url     string `yaml:"url" env-default:""`
+		S3      struct {
+			Endpoint  string `yaml:"endpoint" env-default:""` // e.g. https://s3.eu-central-1.amazonaws.com
+			Region    string `yaml:"region" env-default:"us-east-1"`
+			Bucket    string `yaml:"bucket" env-default:""`
+			Prefix    string `yaml:"prefix" env-default:""` // key prefix of image files, e.g. image/catalog/product/
+			AccessKey string `yaml:"access_key" env-default:""`
+			SecretKey string `yaml:"secret_key" env-default:""`
+			PathStyle bool   `yaml:"path_style" env-default:"false"` // address the bucket as {endpoint}/{bucket}
+		} `yaml:"s3"`
+		Cleanup struct {
+			Pattern       string `yaml:"pattern" env-default:""`               // regexp of file names managed by OCAPI; empty = {uuid}.{jpg|png|gif}
+			Quarantine    string `yaml:"quarantine" env-default:".quarantine"` // folder orphan files are moved to
+			RetentionDays int    `yaml:"retention_days" env-default:"30"`      // quarantined files are deleted after this period
+		} `yaml:"cleanup"`
+		Cache struct {
+			Path   string   `yaml:"path" env-default:""`   // OpenCart image cache directory (DIR_IMAGE/cache/); empty = not maintained
+			Prefix string   `yaml:"prefix" env-default:""` // key prefix of the image cache in the S3 bucket
+			Sizes  []string `yaml:"sizes"`                 // thumbnails generated for new images, e.g. 228x228
+		} `yaml:"cache"`
+		MaxWidth  int `yaml:"max_width" env-default:"0"`  // larger images are downscaled; 0 = no limit
+		MaxHeight int `yaml:"max_height" env-default:"0"` // larger images are downscaled; 0 = no limit
+		Quality   int `yaml:"quality" env-default:"90"`   // JPEG quality of normalized images
 	} `yaml:"images"`
+	Download struct {
+		Path string `yaml:"path" env-default:""` // OpenCart download directory (system/storage/download/)
+	} `yaml:"download"`
 	Product struct {
-		CustomFields []string `yaml:"custom_fields"` // additional allowed custom field names
+		CustomFields       []string      `yaml:"custom_fields"`                           // additional allowed custom field names
+		Transaction        string        `yaml:"transaction" env-default:"product"`       // transaction scope for product saves: product or request
+		SpecialPurge       time.Duration `yaml:"special_purge" env-default:"0"`           // interval of expired specials removal; 0 = disabled
+		ManufacturerStrict bool          `yaml:"manufacturer_strict" env-default:"false"` // product manufacturer must be an existing manufacturer UID
+		Defaults           struct {
+			TaxClass          string `yaml:"tax_class" env-default:"9"`        // tax class ID or title
+			StockStatusIn     string `yaml:"stock_status_in" env-default:"7"`  // stock status ID or name for products in stock
+			StockStatusOut    string `yaml:"stock_status_out" env-default:"5"` // stock status ID or name for products out of stock
+			LengthClass       string `yaml:"length_class" env-default:"0"`     // length class ID or title
+			Minimum           int    `yaml:"minimum" env-default:"1"`          // minimum order quantity
+			Subtract          *bool  `yaml:"subtract"`                         // subtract stock on order; true if not set
+			Shipping          *bool  `yaml:"shipping"`                         // requires shipping; true if not set
+			DateAvailableDays *int   `yaml:"date_available_days"`              // date_available relative to creation in days; -3 if not set
+		} `yaml:"defaults"`
 	} `yaml:"product"`
+	Store struct {
+		Default []int64          `yaml:"default"` // stores new products and categories are published to; [0] if empty
+		Codes   map[string]int64 `yaml:"codes"`   // store codes accepted instead of store IDs
+	} `yaml:"store"`
+	Seo struct {
+		Transliterate bool `yaml:"transliterate" env-default:"false"` // generate keywords from names when not supplied
+	} `yaml:"seo"`
 	Telegram struct {
 		Enabled bool   `yaml:"enabled" env-default:"false"`
 		ApiKey  string `yaml:"api_key" env-default:""`
@@ -0,0 +1,209 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"ocapi/entity"
+	"slices"
+	"strings"
+)
+
+// categoryAncestors returns the IDs from the top level down to the given category by following parent_id;
+// empty for 0. A missing parent ends the walk; a loop in the hierarchy is reported as invalid data.
+// The rows are read FOR UPDATE: in a transaction they stay locked until it ends.
+func (s *MySql) categoryAncestors(ex executor, categoryId int64) ([]int64, error) {
+	query := fmt.Sprintf(`SELECT parent_id FROM %scategory WHERE category_id = ? FOR UPDATE`, s.prefix)
+
+	var path []int64
+	seen := make(map[int64]bool)
+	for id := categoryId; id != 0; {
+		if seen[id] {
+			return nil, fmt.Errorf("category %d: loop in parent hierarchy: %w", categoryId, entity.ErrInvalid)
+		}
+		seen[id] = true
+
+		var parentId int64
+		err := ex.QueryRow(query, id).Scan(&parentId)
+		if errors.Is(err, sql.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, id)
+		id = parentId
+	}
+	slices.Reverse(path)
+	return path, nil
+}
+
+// updateCategoryPaths sets the path of a category and of its subtree, walked by parent_id;
+// only the categories whose stored path differs are rewritten.
+func (s *MySql) updateCategoryPaths(ex executor, categoryId int64, path []int64) error {
+	type node struct {
+		categoryId int64
+		path       []int64
+	}
+	query := fmt.Sprintf(`SELECT category_id FROM %scategory WHERE parent_id = ?`, s.prefix)
+	queue := []node{{categoryId: categoryId, path: path}}
+	seen := map[int64]bool{categoryId: true}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		current, err := s.storedCategoryPath(ex, n.categoryId)
+		if err != nil {
+			return err
+		}
+		if !slices.Equal(current, n.path) {
+			if err = s.writeCategoryPath(ex, n.categoryId, n.path); err != nil {
+				return err
+			}
+		}
+
+		err = queryRows(ex, query, []interface{}{n.categoryId}, func(rows *sql.Rows) error {
+			var childId int64
+			if err := rows.Scan(&childId); err != nil {
+				return err
+			}
+			if !seen[childId] {
+				seen[childId] = true
+				queue = append(queue, node{categoryId: childId, path: append(slices.Clone(n.path), childId)})
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("category %d: children: %v", n.categoryId, err)
+		}
+	}
+	return nil
+}
+
+// storedCategoryPath reads the category_path of a category ordered by level.
+func (s *MySql) storedCategoryPath(ex executor, categoryId int64) ([]int64, error) {
+	query := fmt.Sprintf(`SELECT path_id FROM %scategory_path WHERE category_id = ? ORDER BY level`, s.prefix)
+
+	var path []int64
+	err := queryRows(ex, query, []interface{}{categoryId}, func(rows *sql.Rows) error {
+		var pathId int64
+		if err := rows.Scan(&pathId); err != nil {
+			return err
+		}
+		path = append(path, pathId)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("category %d: path: %v", categoryId, err)
+	}
+	return path, nil
+}
+
+// writeCategoryPath replaces the category_path rows of a category.
+func (s *MySql) writeCategoryPath(ex executor, categoryId int64, path []int64) error {
+	query := fmt.Sprintf(`DELETE FROM %scategory_path WHERE category_id = ?`, s.prefix)
+	if _, err := ex.Exec(query, categoryId); err != nil {
+		return fmt.Errorf("category %d: delete path: %v", categoryId, err)
+	}
+	return s.insertCategoryPath(ex, categoryId, path)
+}
+
+// insertCategoryPath inserts a row per ancestor of the category, the top level one at level 0.
+func (s *MySql) insertCategoryPath(ex executor, categoryId int64, path []int64) error {
+	if len(path) == 0 {
+		return nil
+	}
+	placeholders := make([]string, len(path))
+	args := make([]interface{}, 0, 3*len(path))
+	for level, pathId := range path {
+		placeholders[level] = "(?, ?, ?)"
+		args = append(args, categoryId, pathId, level)
+	}
+	query := fmt.Sprintf(`INSERT INTO %scategory_path (category_id, path_id, level) VALUES %s`,
+		s.prefix, strings.Join(placeholders, ", "))
+	if _, err := ex.Exec(query, args...); err != nil {
+		return fmt.Errorf("category %d: insert path: %v", categoryId, err)
+	}
+	return nil
+}
+
+// RepairCategoryPaths rebuilds the whole category_path table from parent_id in one transaction,
+// like the repair in the OpenCart admin. The categories are read with locks, so no category is moved
+// while the paths are rebuilt.
+func (s *MySql) RepairCategoryPaths() (*entity.CategoryRepairResult, error) {
+	var paths map[int64][]int64
+	var skipped []int64
+	err := s.withTx(func(tx *sql.Tx) error {
+		parents := make(map[int64]int64)
+		var ids []int64
+		query := fmt.Sprintf(`SELECT category_id, parent_id FROM %scategory ORDER BY category_id FOR UPDATE`, s.prefix)
+		err := queryRows(tx, query, nil, func(rows *sql.Rows) error {
+			var categoryId, parentId int64
+			if err := rows.Scan(&categoryId, &parentId); err != nil {
+				return err
+			}
+			parents[categoryId] = parentId
+			ids = append(ids, categoryId)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("read categories: %v", err)
+		}
+
+		paths, skipped = categoryPaths(ids, parents)
+		if _, err = tx.Exec(fmt.Sprintf(`DELETE FROM %scategory_path`, s.prefix)); err != nil {
+			return fmt.Errorf("delete paths: %v", err)
+		}
+		for _, categoryId := range ids {
+			if err := s.insertCategoryPath(tx, categoryId, paths[categoryId]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entity.CategoryRepairResult{Categories: len(paths), Skipped: skipped}, nil
+}
+
+// categoryPaths computes the path of every category from the parent IDs. Categories whose chain
+// of parents reaches a missing category or a loop get no path and are returned as skipped.
+func categoryPaths(ids []int64, parents map[int64]int64) (map[int64][]int64, []int64) {
+	paths := make(map[int64][]int64, len(ids))
+	failed := make(map[int64]bool)
+	var skipped []int64
+
+	for _, categoryId := range ids {
+		// walk up until the top level or a category with a known outcome
+		var chain []int64
+		var base []int64
+		onChain := make(map[int64]bool)
+		ok := true
+		for id := categoryId; id != 0; {
+			if path, found := paths[id]; found {
+				base = path
+				break
+			}
+			parentId, exists := parents[id]
+			if failed[id] || !exists || onChain[id] {
+				ok = false
+				break
+			}
+			onChain[id] = true
+			chain = append(chain, id)
+			id = parentId
+		}
+
+		for i := len(chain) - 1; i >= 0; i-- {
+			if !ok {
+				failed[chain[i]] = true
+				skipped = append(skipped, chain[i])
+				continue
+			}
+			base = append(slices.Clone(base), chain[i])
+			paths[chain[i]] = base
+		}
+	}
+	slices.Sort(skipped)
+	return paths, skipped
+}
@@ -0,0 +1,196 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"ocapi/entity"
+	"ocapi/internal/config"
+	"strings"
+	"time"
+)
+
+// dictionary describes an OpenCart table whose entries are referenced by ID or by name;
+// a name matches any of the name columns, e.g. a unit code or a title.
+type dictionary struct {
+	table       string
+	idColumn    string
+	nameColumns []string
+}
+
+var (
+	taxClasses    = dictionary{table: "tax_class", idColumn: "tax_class_id", nameColumns: []string{"title"}}
+	stockStatuses = dictionary{table: "stock_status", idColumn: "stock_status_id", nameColumns: []string{"name"}}
+	lengthClasses = dictionary{table: "length_class_description", idColumn: "length_class_id", nameColumns: []string{"unit", "title"}}
+	weightClasses = dictionary{table: "weight_class_description", idColumn: "weight_class_id", nameColumns: []string{"unit", "title"}}
+)
+
+// productDefaults holds the column values of new products resolved from the configuration.
+type productDefaults struct {
+	taxClassId        int64
+	stockStatusIn     int64
+	stockStatusOut    int64
+	lengthClassId     int64
+	minimum           int
+	subtract          bool
+	shipping          bool
+	dateAvailableDays int
+}
+
+// loadProductDefaults resolves the configured product defaults; names are looked up in the database.
+func (s *MySql) loadProductDefaults(conf *config.Config) (productDefaults, error) {
+	defaults := conf.Product.Defaults
+	d := productDefaults{
+		minimum:           defaults.Minimum,
+		subtract:          true,
+		shipping:          true,
+		dateAvailableDays: -3,
+	}
+	if defaults.Subtract != nil {
+		d.subtract = *defaults.Subtract
+	}
+	if defaults.Shipping != nil {
+		d.shipping = *defaults.Shipping
+	}
+	if defaults.DateAvailableDays != nil {
+		d.dateAvailableDays = *defaults.DateAvailableDays
+	}
+
+	var err error
+	if d.taxClassId, err = s.resolveId(s.db, taxClasses, entity.IdOrName(defaults.TaxClass)); err != nil {
+		return d, fmt.Errorf("tax class: %w", err)
+	}
+	if d.stockStatusIn, err = s.resolveId(s.db, stockStatuses, entity.IdOrName(defaults.StockStatusIn)); err != nil {
+		return d, fmt.Errorf("stock status in: %w", err)
+	}
+	if d.stockStatusOut, err = s.resolveId(s.db, stockStatuses, entity.IdOrName(defaults.StockStatusOut)); err != nil {
+		return d, fmt.Errorf("stock status out: %w", err)
+	}
+	if d.lengthClassId, err = s.resolveId(s.db, lengthClasses, entity.IdOrName(defaults.LengthClass)); err != nil {
+		return d, fmt.Errorf("length class: %w", err)
+	}
+	return d, nil
+}
+
+// resolveId returns the ID of a dictionary entry given by ID or by name. IDs are returned as is,
+// names are looked up once and cached. An unknown name is reported as invalid data.
+func (s *MySql) resolveId(ex executor, dict dictionary, value entity.IdOrName) (int64, error) {
+	if id, ok := value.Id(); ok {
+		return id, nil
+	}
+
+	key := dict.table + ":" + string(value)
+	s.dictionaryMu.Lock()
+	id, ok := s.dictionaryIds[key]
+	s.dictionaryMu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	conditions := make([]string, len(dict.nameColumns))
+	args := make([]interface{}, len(dict.nameColumns))
+	for i, column := range dict.nameColumns {
+		conditions[i] = column + " = ?"
+		args[i] = string(value)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s%s WHERE %s LIMIT 1", dict.idColumn, s.prefix, dict.table, strings.Join(conditions, " OR "))
+	err := ex.QueryRow(query, args...).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s %q: %w", dict.table, value, entity.ErrInvalid)
+		}
+		return 0, fmt.Errorf("%s lookup: %v", dict.table, err)
+	}
+
+	s.dictionaryMu.Lock()
+	s.dictionaryIds[key] = id
+	s.dictionaryMu.Unlock()
+	return id, nil
+}
+
+// stockStatusId returns the stock status of a product by its quantity, using the product
+// overrides or the configured defaults.
+func (s *MySql) stockStatusId(ex executor, product *entity.ProductData) (int64, error) {
+	inStock, outOfStock := s.defaults.stockStatusIn, s.defaults.stockStatusOut
+	var err error
+	if product.StockStatusIn != "" {
+		if inStock, err = s.resolveId(ex, stockStatuses, product.StockStatusIn); err != nil {
+			return 0, err
+		}
+	}
+	if product.StockStatusOut != "" {
+		if outOfStock, err = s.resolveId(ex, stockStatuses, product.StockStatusOut); err != nil {
+			return 0, err
+		}
+	}
+	return product.StockStatusID(inStock, outOfStock), nil
+}
+
+// productOverrides returns the product columns explicitly set in the request.
+func (s *MySql) productOverrides(ex executor, product *entity.ProductData) (map[string]interface{}, error) {
+	overrides := make(map[string]interface{})
+	if product.TaxClass != "" {
+		taxClassId, err := s.resolveId(ex, taxClasses, product.TaxClass)
+		if err != nil {
+			return nil, err
+		}
+		overrides["tax_class_id"] = taxClassId
+	}
+	if product.LengthClass != "" {
+		lengthClassId, err := s.resolveId(ex, lengthClasses, product.LengthClass)
+		if err != nil {
+			return nil, err
+		}
+		overrides["length_class_id"] = lengthClassId
+	}
+	if product.Length != nil {
+		overrides["length"] = *product.Length
+	}
+	if product.Width != nil {
+		overrides["width"] = *product.Width
+	}
+	if product.Height != nil {
+		overrides["height"] = *product.Height
+	}
+	if product.Minimum > 0 {
+		overrides["minimum"] = product.Minimum
+	}
+	if product.Subtract != nil {
+		overrides["subtract"] = boolFlag(*product.Subtract)
+	}
+	if product.Shipping != nil {
+		overrides["shipping"] = boolFlag(*product.Shipping)
+	}
+	if product.DateAvailable != nil {
+		overrides["date_available"] = *product.DateAvailable
+	}
+	return overrides, nil
+}
+
+// weightClassId returns the weight class given by unit code or title, or the weight class ID of the request.
+func (s *MySql) weightClassId(ex executor, product *entity.ProductData) (int64, error) {
+	if product.WeightClass == "" {
+		return int64(product.WeightClassId), nil
+	}
+	return s.resolveId(ex, weightClasses, product.WeightClass)
+}
+
+// defaultProductFields returns the configured default columns of a new product.
+func (s *MySql) defaultProductFields() map[string]interface{} {
+	return map[string]interface{}{
+		"tax_class_id":    s.defaults.taxClassId,
+		"minimum":         s.defaults.minimum,
+		"subtract":        boolFlag(s.defaults.subtract),
+		"shipping":        boolFlag(s.defaults.shipping),
+		"length_class_id": s.defaults.lengthClassId,
+		"date_available":  time.Now().AddDate(0, 0, s.defaults.dateAvailableDays),
+	}
+}
+
+// boolFlag converts a boolean to OpenCart's tinyint flag.
+func boolFlag(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
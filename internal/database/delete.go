@@ -0,0 +1,132 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"ocapi/entity"
+)
+
+// productTables lists tables holding product-owned rows keyed by product_id; tables missing
+// in the installed OpenCart version are skipped.
+var productTables = []string{
+	"product_description",
+	"product_to_category",
+	"product_to_store",
+	"product_to_layout",
+	"product_attribute",
+	"product_special",
+	"product_discount",
+	"product_image",
+	"product_option_value",
+	"product_option",
+	"product_reward",
+	"product_filter",
+	"product_to_download",
+}
+
+// DeleteProduct removes a product identified by UID together with all its related rows
+// inside a single transaction. Returns the image paths no longer used by any other product.
+func (s *MySql) DeleteProduct(uid string) ([]string, error) {
+	var images []string
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		images, err = s.deleteProduct(tx, uid)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// deleteProduct removes the product rows and returns its images that became unused.
+func (s *MySql) deleteProduct(ex executor, uid string) ([]string, error) {
+	productId, err := s.getProductByUID(ex, uid)
+	if err != nil {
+		return nil, fmt.Errorf("product search: %v", err)
+	}
+	if productId == 0 {
+		return nil, fmt.Errorf("product %s %w", uid, entity.ErrNotFound)
+	}
+
+	images, err := s.productImages(ex, productId)
+	if err != nil {
+		return nil, fmt.Errorf("product %s: images: %v", uid, err)
+	}
+
+	for _, table := range productTables {
+		columns, err := s.readStructure(table)
+		if err != nil {
+			return nil, fmt.Errorf("product %s: %s: %v", uid, table, err)
+		}
+		if len(columns) == 0 {
+			continue
+		}
+		query := fmt.Sprintf(`DELETE FROM %s%s WHERE product_id=?`, s.prefix, table)
+		if _, err = ex.Exec(query, productId); err != nil {
+			return nil, fmt.Errorf("product %s: %s: %v", uid, table, err)
+		}
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %sproduct_related WHERE product_id=? OR related_id=?`, s.prefix)
+	if _, err = ex.Exec(query, productId, productId); err != nil {
+		return nil, fmt.Errorf("product %s: product_related: %v", uid, err)
+	}
+
+	if err = s.deleteSeoKeywords(ex, "product_id", productId); err != nil {
+		return nil, fmt.Errorf("product %s: %v", uid, err)
+	}
+
+	query = fmt.Sprintf(`DELETE FROM %sproduct WHERE product_id=?`, s.prefix)
+	if _, err = ex.Exec(query, productId); err != nil {
+		return nil, fmt.Errorf("product %s: %v", uid, err)
+	}
+
+	unused := make([]string, 0, len(images))
+	for _, image := range images {
+		inUse, err := s.imageInUse(ex, image)
+		if err != nil {
+			return nil, fmt.Errorf("product %s: image %s: %v", uid, image, err)
+		}
+		if !inUse {
+			unused = append(unused, image)
+		}
+	}
+	return unused, nil
+}
+
+// productImages returns the main and additional image paths of a product.
+func (s *MySql) productImages(ex executor, productId int64) ([]string, error) {
+	query := fmt.Sprintf(`SELECT image FROM %sproduct WHERE product_id=? UNION SELECT image FROM %sproduct_image WHERE product_id=?`,
+		s.prefix, s.prefix)
+	rows, err := ex.Query(query, productId, productId)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	var images []string
+	for rows.Next() {
+		var image sql.NullString
+		if err = rows.Scan(&image); err != nil {
+			return nil, fmt.Errorf("scan: %v", err)
+		}
+		if image.Valid && image.String != "" {
+			images = append(images, image.String)
+		}
+	}
+	return images, rows.Err()
+}
+
+// imageInUse reports whether the image path is still referenced by any product.
+func (s *MySql) imageInUse(ex executor, image string) (bool, error) {
+	query := fmt.Sprintf(`SELECT (SELECT COUNT(*) FROM %sproduct WHERE image=?) + (SELECT COUNT(*) FROM %sproduct_image WHERE image=?)`,
+		s.prefix, s.prefix)
+	var count int
+	if err := ex.QueryRow(query, image, image).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
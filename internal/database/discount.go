@@ -0,0 +1,78 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"ocapi/entity"
+)
+
+// SaveProductDiscounts replaces the quantity discount tiers of a batch of products. The request
+// is the complete discount set for each product it mentions: existing rows are deleted and the
+// new tiers inserted inside one transaction per product. Tiers of the same customer group and
+// quantity with intersecting date ranges are rejected. Results are reported per product.
+func (s *MySql) SaveProductDiscounts(discounts []*entity.ProductDiscount, report bool) ([]*entity.ItemResult, error) {
+	groups := make(map[string][]*entity.ProductDiscount)
+	order := make([]string, 0)
+	for _, discount := range discounts {
+		if _, ok := groups[discount.ProductUid]; !ok {
+			order = append(order, discount.ProductUid)
+		}
+		groups[discount.ProductUid] = append(groups[discount.ProductUid], discount)
+	}
+
+	return saveItems(order, report,
+		func(uid string) string { return uid },
+		func(uid string) error {
+			if err := checkDiscountOverlap(groups[uid]); err != nil {
+				return fmt.Errorf("product discount %s: %w", uid, err)
+			}
+			return s.withTx(func(tx *sql.Tx) error {
+				return s.replaceProductDiscounts(tx, uid, groups[uid])
+			})
+		})
+}
+
+// checkDiscountOverlap returns an error if any two tiers of the set overlap.
+func checkDiscountOverlap(discounts []*entity.ProductDiscount) error {
+	for i := 0; i < len(discounts); i++ {
+		for j := i + 1; j < len(discounts); j++ {
+			if discounts[i].Overlaps(discounts[j]) {
+				return fmt.Errorf("tiers for group %d quantity %d overlap: %w",
+					discounts[i].GroupId, discounts[i].Quantity, entity.ErrInvalid)
+			}
+		}
+	}
+	return nil
+}
+
+// replaceProductDiscounts deletes all discount rows of the product and inserts the given tiers.
+func (s *MySql) replaceProductDiscounts(ex executor, uid string, discounts []*entity.ProductDiscount) error {
+	productId, err := s.getProductByUID(ex, uid)
+	if err != nil {
+		return fmt.Errorf("product discount %s: product search: %v", uid, err)
+	}
+	if productId == 0 {
+		return fmt.Errorf("product discount %s: product %w", uid, entity.ErrNotFound)
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %sproduct_discount WHERE product_id=?`, s.prefix)
+	if _, err = ex.Exec(query, productId); err != nil {
+		return fmt.Errorf("product discount %s: delete: %v", uid, err)
+	}
+
+	for _, discount := range discounts {
+		discountData := map[string]interface{}{
+			"product_id":        productId,
+			"customer_group_id": discount.GroupId,
+			"quantity":          discount.Quantity,
+			"priority":          discount.Priority,
+			"price":             discount.Price,
+			"date_start":        discount.DateStart,
+			"date_end":          discount.DateEnd,
+		}
+		if _, err = s.insert(ex, "product_discount", discountData); err != nil {
+			return fmt.Errorf("product discount %s: %v", uid, err)
+		}
+	}
+	return nil
+}
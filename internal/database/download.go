@@ -0,0 +1,104 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"ocapi/entity"
+	"time"
+)
+
+// SaveDownload creates or updates a download with its descriptions by UID.
+// Returns the file name of the replaced file, empty if the download is new.
+func (s *MySql) SaveDownload(download *entity.DownloadData) (string, error) {
+	var previous string
+	err := s.withTx(func(tx *sql.Tx) error {
+		downloadId, filename, err := s.getDownloadByUID(tx, download.DownloadUid)
+		if err != nil {
+			return fmt.Errorf("download search: %v", err)
+		}
+
+		if downloadId == 0 {
+			userData := map[string]interface{}{
+				"download_uid": download.DownloadUid,
+				"filename":     download.Filename,
+				"mask":         download.Mask,
+				"date_added":   time.Now(),
+			}
+			downloadId, err = s.insert(tx, "download", userData)
+		} else {
+			previous = filename
+			err = s.update(tx, "download",
+				map[string]interface{}{"filename": download.Filename, "mask": download.Mask},
+				"download_id=?", downloadId)
+		}
+		if err != nil {
+			return fmt.Errorf("download %s: %v", download.DownloadUid, err)
+		}
+
+		for _, desc := range download.Descriptions {
+			if desc.LanguageId, err = s.resolveLanguage(desc.LanguageId, desc.Language); err != nil {
+				return fmt.Errorf("download %s: %w", download.DownloadUid, err)
+			}
+			err = s.upsertRow(tx, "download_description",
+				map[string]interface{}{"download_id": downloadId, "language_id": desc.LanguageId},
+				map[string]interface{}{"name": desc.Name})
+			if err != nil {
+				return fmt.Errorf("download %s: description: %v", download.DownloadUid, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return previous, nil
+}
+
+// SetProductDownloads replaces the downloads linked to a product with the given download UIDs.
+func (s *MySql) SetProductDownloads(productUid string, downloadUids []string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		productId, err := s.getProductByUID(tx, productUid)
+		if err != nil {
+			return fmt.Errorf("product search: %v", err)
+		}
+		if productId == 0 {
+			return fmt.Errorf("product %s %w", productUid, entity.ErrNotFound)
+		}
+
+		query := fmt.Sprintf(`DELETE FROM %sproduct_to_download WHERE product_id=?`, s.prefix)
+		if _, err = tx.Exec(query, productId); err != nil {
+			return fmt.Errorf("product %s: delete downloads: %v", productUid, err)
+		}
+
+		query = fmt.Sprintf(`INSERT IGNORE INTO %sproduct_to_download (product_id, download_id) VALUES (?, ?)`, s.prefix)
+		for _, uid := range downloadUids {
+			downloadId, _, err := s.getDownloadByUID(tx, uid)
+			if err != nil {
+				return fmt.Errorf("download search: %v", err)
+			}
+			if downloadId == 0 {
+				return fmt.Errorf("product %s: download %s %w", productUid, uid, entity.ErrNotFound)
+			}
+			if _, err = tx.Exec(query, productId, downloadId); err != nil {
+				return fmt.Errorf("product %s: download %s: %v", productUid, uid, err)
+			}
+		}
+		return nil
+	})
+}
+
+// getDownloadByUID returns the download_id and stored file name for a download UID, or 0 if not found.
+func (s *MySql) getDownloadByUID(ex executor, uid string) (int64, string, error) {
+	query := fmt.Sprintf(`SELECT download_id, filename FROM %sdownload WHERE download_uid=? LIMIT 1`, s.prefix)
+	var downloadId int64
+	var filename string
+	err := ex.QueryRow(query, uid).Scan(&downloadId, &filename)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, "", nil
+		}
+		return 0, "", err
+	}
+	return downloadId, filename, nil
+}
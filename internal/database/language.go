@@ -0,0 +1,78 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"ocapi/entity"
+	"strings"
+)
+
+// loadLanguages reads the shop languages; they are looked up by ID and by lower-case code.
+func (s *MySql) loadLanguages() error {
+	query := fmt.Sprintf(`SELECT language_id, name, code, locale, sort_order, status
+			FROM %slanguage ORDER BY sort_order, language_id`, s.prefix)
+
+	var languages []*entity.Language
+	ids := make(map[int64]*entity.Language)
+	codes := make(map[string]*entity.Language)
+	err := queryRows(s.db, query, nil, func(rows *sql.Rows) error {
+		language := &entity.Language{}
+		if err := rows.Scan(&language.LanguageId, &language.Name, &language.Code, &language.Locale,
+			&language.SortOrder, &language.Status); err != nil {
+			return err
+		}
+		languages = append(languages, language)
+		ids[language.LanguageId] = language
+		codes[strings.ToLower(language.Code)] = language
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.languageMu.Lock()
+	s.languages, s.languageIds, s.languageCodes = languages, ids, codes
+	s.languageMu.Unlock()
+	return nil
+}
+
+// Languages returns the shop languages in their sort order.
+func (s *MySql) Languages() []*entity.Language {
+	s.languageMu.RLock()
+	defer s.languageMu.RUnlock()
+	return s.languages
+}
+
+// languageCode returns the code of a language by ID, empty if it is unknown.
+func (s *MySql) languageCode(id int64) string {
+	s.languageMu.RLock()
+	defer s.languageMu.RUnlock()
+	if language := s.languageIds[id]; language != nil {
+		return language.Code
+	}
+	return ""
+}
+
+// resolveLanguage returns the ID of the language given by code or, without a code, by ID.
+// Unknown and disabled languages are rejected as invalid.
+func (s *MySql) resolveLanguage(id int64, code string) (int64, error) {
+	s.languageMu.RLock()
+	defer s.languageMu.RUnlock()
+
+	var language *entity.Language
+	if code != "" {
+		language = s.languageCodes[strings.ToLower(code)]
+		if language == nil {
+			return 0, fmt.Errorf("language %s: %w", code, entity.ErrInvalid)
+		}
+	} else {
+		language = s.languageIds[id]
+		if language == nil {
+			return 0, fmt.Errorf("language %d: %w", id, entity.ErrInvalid)
+		}
+	}
+	if !language.Status {
+		return 0, fmt.Errorf("language %s disabled: %w", language.Code, entity.ErrInvalid)
+	}
+	return language.LanguageId, nil
+}
@@ -0,0 +1,216 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"ocapi/entity"
+)
+
+// SaveManufacturers upserts a batch of manufacturers by UID. A manufacturer without a UID but with
+// the same name, e.g. created from a product, is adopted instead of creating a duplicate.
+func (s *MySql) SaveManufacturers(manufacturers []*entity.Manufacturer, report bool) ([]*entity.ItemResult, error) {
+	return saveItems(manufacturers, report,
+		func(manufacturer *entity.Manufacturer) string { return manufacturer.Uid },
+		func(manufacturer *entity.Manufacturer) error {
+			return s.withTx(func(tx *sql.Tx) error {
+				return s.saveManufacturer(tx, manufacturer)
+			})
+		})
+}
+
+// saveManufacturer writes the manufacturer row, its store links, descriptions and SEO keywords.
+// Store links are synchronised only when the request lists stores; new manufacturers get the default stores.
+func (s *MySql) saveManufacturer(ex executor, manufacturer *entity.Manufacturer) error {
+	stores, err := s.resolveStores(manufacturer.Stores, manufacturer.StoreCodes)
+	if err != nil {
+		return fmt.Errorf("manufacturer %s: %w", manufacturer.Uid, err)
+	}
+
+	manufacturerId, err := s.findManufacturer(ex, manufacturer.Uid, manufacturer.Name)
+	if err != nil {
+		return fmt.Errorf("manufacturer %s: lookup: %v", manufacturer.Uid, err)
+	}
+
+	userData := map[string]interface{}{
+		"manufacturer_uid": manufacturer.Uid,
+		"name":             manufacturer.Name,
+		"sort_order":       manufacturer.SortOrder,
+	}
+	if manufacturer.Image != nil {
+		userData["image"] = *manufacturer.Image
+	}
+
+	if manufacturerId == 0 {
+		if manufacturerId, err = s.insert(ex, "manufacturer", userData); err != nil {
+			return fmt.Errorf("manufacturer %s: %v", manufacturer.Uid, err)
+		}
+		if stores == nil {
+			stores = s.defaultStores
+		}
+	} else {
+		err = s.update(ex, "manufacturer", userData, "manufacturer_id=?", manufacturerId)
+		if err != nil {
+			return fmt.Errorf("manufacturer %s: %v", manufacturer.Uid, err)
+		}
+	}
+
+	if stores != nil {
+		if err = s.syncStoreLinks(ex, "manufacturer_to_store", "manufacturer_id", manufacturerId, stores, false); err != nil {
+			return fmt.Errorf("manufacturer %s: manufacturer to store: %v", manufacturer.Uid, err)
+		}
+	}
+
+	descriptionTable, err := s.readStructure("manufacturer_description")
+	if err != nil {
+		return fmt.Errorf("manufacturer %s: %v", manufacturer.Uid, err)
+	}
+	for _, description := range manufacturer.Descriptions {
+		if description.LanguageId, err = s.resolveLanguage(description.LanguageId, description.Language); err != nil {
+			return fmt.Errorf("manufacturer %s: %w", manufacturer.Uid, err)
+		}
+		if len(descriptionTable) > 0 {
+			key := map[string]interface{}{
+				"manufacturer_id": manufacturerId,
+				"language_id":     description.LanguageId,
+			}
+			data := map[string]interface{}{
+				"name":             manufacturer.Name,
+				"description":      description.Description,
+				"meta_title":       description.MetaTitle,
+				"meta_h1":          description.MetaH1,
+				"meta_description": description.MetaDescription,
+				"meta_keyword":     description.MetaKeyword,
+			}
+			if err = s.upsertRow(ex, "manufacturer_description", key, data); err != nil {
+				return fmt.Errorf("manufacturer %s: description: %v", manufacturer.Uid, err)
+			}
+		}
+
+		err = s.saveSeoKeyword(ex, "manufacturer_id", manufacturerId, description.LanguageId, description.SeoKeyword, manufacturer.Name)
+		if err != nil {
+			return fmt.Errorf("manufacturer %s: %v", manufacturer.Uid, err)
+		}
+	}
+	return nil
+}
+
+// findManufacturer returns the manufacturer_id by UID or, failing that, of a manufacturer
+// with the same name and no UID; 0 if there is none.
+func (s *MySql) findManufacturer(ex executor, uid, name string) (int64, error) {
+	manufacturerId, err := s.getManufacturerByUID(ex, uid)
+	if err != nil || manufacturerId != 0 {
+		return manufacturerId, err
+	}
+
+	query := fmt.Sprintf(`SELECT manufacturer_id FROM %smanufacturer WHERE name=? AND manufacturer_uid='' LIMIT 1`, s.prefix)
+	err = ex.QueryRow(query, name).Scan(&manufacturerId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+	return manufacturerId, nil
+}
+
+// getManufacturerByUID returns the manufacturer_id for a given UID, or 0 if not found.
+func (s *MySql) getManufacturerByUID(ex executor, uid string) (int64, error) {
+	query := fmt.Sprintf(`SELECT manufacturer_id FROM %smanufacturer WHERE manufacturer_uid=? LIMIT 1`, s.prefix)
+	var manufacturerId int64
+	err := ex.QueryRow(query, uid).Scan(&manufacturerId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return manufacturerId, nil
+}
+
+// resolveManufacturer returns the ID of a referenced manufacturer; ErrNotFound if it does not exist.
+func (s *MySql) resolveManufacturer(ex executor, ref entity.ManufacturerRef) (int64, error) {
+	if ref.Uid != "" {
+		manufacturerId, err := s.getManufacturerByUID(ex, ref.Uid)
+		if err != nil {
+			return 0, err
+		}
+		if manufacturerId == 0 {
+			return 0, fmt.Errorf("manufacturer %s: %w", ref.Uid, entity.ErrNotFound)
+		}
+		return manufacturerId, nil
+	}
+
+	var count int
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %smanufacturer WHERE manufacturer_id=?`, s.prefix)
+	if err := ex.QueryRow(query, ref.Id).Scan(&count); err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("manufacturer %d: %w", ref.Id, entity.ErrNotFound)
+	}
+	return ref.Id, nil
+}
+
+// MergeManufacturers moves all products of the source manufacturer to the target one and deletes
+// the source with its store links, descriptions and SEO keywords, in one transaction.
+// The target takes over the UID of the source if it has none.
+func (s *MySql) MergeManufacturers(source, target entity.ManufacturerRef) (*entity.ManufacturerMergeResult, error) {
+	result := &entity.ManufacturerMergeResult{}
+	err := s.withTx(func(tx *sql.Tx) error {
+		sourceId, err := s.resolveManufacturer(tx, source)
+		if err != nil {
+			return fmt.Errorf("source: %w", err)
+		}
+		targetId, err := s.resolveManufacturer(tx, target)
+		if err != nil {
+			return fmt.Errorf("target: %w", err)
+		}
+		if sourceId == targetId {
+			return fmt.Errorf("source and target are the same manufacturer: %w", entity.ErrInvalid)
+		}
+		result.ManufacturerId = targetId
+
+		query := fmt.Sprintf(`UPDATE %sproduct SET manufacturer_id=? WHERE manufacturer_id=?`, s.prefix)
+		res, err := tx.Exec(query, targetId, sourceId)
+		if err != nil {
+			return fmt.Errorf("move products: %v", err)
+		}
+		if result.Products, err = res.RowsAffected(); err != nil {
+			return err
+		}
+
+		var sourceUid string
+		query = fmt.Sprintf(`SELECT manufacturer_uid FROM %smanufacturer WHERE manufacturer_id=?`, s.prefix)
+		if err = tx.QueryRow(query, sourceId).Scan(&sourceUid); err != nil {
+			return fmt.Errorf("source uid: %v", err)
+		}
+
+		tables := []string{"manufacturer_to_store", "manufacturer_to_layout", "manufacturer_description", "manufacturer"}
+		for _, table := range tables {
+			columns, err := s.readStructure(table)
+			if err != nil {
+				return err
+			}
+			if len(columns) == 0 {
+				continue
+			}
+			query = fmt.Sprintf(`DELETE FROM %s%s WHERE manufacturer_id=?`, s.prefix, table)
+			if _, err = tx.Exec(query, sourceId); err != nil {
+				return fmt.Errorf("delete %s: %v", table, err)
+			}
+		}
+		if err = s.deleteSeoKeywords(tx, "manufacturer_id", sourceId); err != nil {
+			return err
+		}
+
+		if sourceUid != "" {
+			query = fmt.Sprintf(`UPDATE %smanufacturer SET manufacturer_uid=? WHERE manufacturer_id=? AND manufacturer_uid=''`, s.prefix)
+			if _, err = tx.Exec(query, sourceUid, targetId); err != nil {
+				return fmt.Errorf("target uid: %v", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
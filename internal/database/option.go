@@ -0,0 +1,335 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"ocapi/entity"
+	"strings"
+)
+
+// SaveOptions upserts a batch of options with their descriptions and values; each option
+// is written inside its own transaction. Values absent from the request are kept.
+func (s *MySql) SaveOptions(options []*entity.Option, report bool) ([]*entity.ItemResult, error) {
+	return saveItems(options, report,
+		func(option *entity.Option) string { return option.Uid },
+		func(option *entity.Option) error {
+			return s.withTx(func(tx *sql.Tx) error {
+				return s.saveOption(tx, option)
+			})
+		})
+}
+
+// saveOption upserts a single option, its descriptions and values.
+func (s *MySql) saveOption(ex executor, option *entity.Option) error {
+	optionId, err := s.getOptionByUID(ex, option.Uid)
+	if err != nil {
+		return fmt.Errorf("option search: %v", err)
+	}
+
+	if optionId == 0 {
+		userData := map[string]interface{}{
+			"option_uid": option.Uid,
+			"type":       option.Type,
+			"sort_order": option.SortOrder,
+		}
+		optionId, err = s.insert(ex, "option", userData)
+	} else {
+		query := fmt.Sprintf("UPDATE `%soption` SET type = ?, sort_order = ? WHERE option_id = ?", s.prefix)
+		_, err = ex.Exec(query, option.Type, option.SortOrder, optionId)
+	}
+	if err != nil {
+		return fmt.Errorf("option %s: %v", option.Uid, err)
+	}
+
+	for _, desc := range option.Descriptions {
+		if desc.LanguageId, err = s.resolveLanguage(desc.LanguageId, desc.Language); err != nil {
+			return fmt.Errorf("option %s: %w", option.Uid, err)
+		}
+		err = s.upsertRow(ex, "option_description",
+			map[string]interface{}{"option_id": optionId, "language_id": desc.LanguageId},
+			map[string]interface{}{"name": desc.Name})
+		if err != nil {
+			return fmt.Errorf("option %s: description: %v", option.Uid, err)
+		}
+	}
+
+	for _, value := range option.Values {
+		if err = s.saveOptionValue(ex, optionId, value); err != nil {
+			return fmt.Errorf("option %s: value %s: %w", option.Uid, value.Uid, err)
+		}
+	}
+	return nil
+}
+
+// saveOptionValue upserts a single option value and its descriptions.
+func (s *MySql) saveOptionValue(ex executor, optionId int64, value *entity.OptionValue) error {
+	optionValueId, err := s.getOptionValueByUID(ex, optionId, value.Uid)
+	if err != nil {
+		return fmt.Errorf("value search: %v", err)
+	}
+
+	if optionValueId == 0 {
+		userData := map[string]interface{}{
+			"option_id":        optionId,
+			"option_value_uid": value.Uid,
+			"image":            value.Image,
+			"sort_order":       value.SortOrder,
+		}
+		optionValueId, err = s.insert(ex, "option_value", userData)
+	} else {
+		err = s.update(ex, "option_value",
+			map[string]interface{}{"image": value.Image, "sort_order": value.SortOrder},
+			"option_value_id=?", optionValueId)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, desc := range value.Descriptions {
+		if desc.LanguageId, err = s.resolveLanguage(desc.LanguageId, desc.Language); err != nil {
+			return err
+		}
+		err = s.upsertRow(ex, "option_value_description",
+			map[string]interface{}{"option_value_id": optionValueId, "language_id": desc.LanguageId},
+			map[string]interface{}{"option_id": optionId, "name": desc.Name})
+		if err != nil {
+			return fmt.Errorf("description: %v", err)
+		}
+	}
+	return nil
+}
+
+// SaveProductOptions synchronises options of a batch of products. The request is treated as
+// the complete option set for each product: existing product options and values are updated
+// in place (keeping their IDs for carts and orders), new ones inserted, and the rest removed.
+// Each product is written inside its own transaction; results are reported per product.
+func (s *MySql) SaveProductOptions(productOptions []*entity.ProductOption, report bool) ([]*entity.ItemResult, error) {
+	groups := make(map[string][]*entity.ProductOption)
+	order := make([]string, 0)
+	for _, productOption := range productOptions {
+		if _, ok := groups[productOption.ProductUid]; !ok {
+			order = append(order, productOption.ProductUid)
+		}
+		groups[productOption.ProductUid] = append(groups[productOption.ProductUid], productOption)
+	}
+
+	return saveItems(order, report,
+		func(uid string) string { return uid },
+		func(uid string) error {
+			return s.withTx(func(tx *sql.Tx) error {
+				return s.saveProductOptions(tx, uid, groups[uid])
+			})
+		})
+}
+
+// saveProductOptions replaces the option set of a single product.
+func (s *MySql) saveProductOptions(ex executor, uid string, productOptions []*entity.ProductOption) error {
+	productId, err := s.getProductByUID(ex, uid)
+	if err != nil {
+		return fmt.Errorf("product option %s: product search: %v", uid, err)
+	}
+	if productId == 0 {
+		return fmt.Errorf("product option %s: product %w", uid, entity.ErrNotFound)
+	}
+
+	keepOptions := make([]int64, 0, len(productOptions))
+	keepValues := make([]int64, 0)
+	for _, productOption := range productOptions {
+		optionId, err := s.getOptionByUID(ex, productOption.OptionUid)
+		if err != nil {
+			return fmt.Errorf("product option %s: option search: %v", uid, err)
+		}
+		if optionId == 0 {
+			return fmt.Errorf("product option %s: option %s %w", uid, productOption.OptionUid, entity.ErrNotFound)
+		}
+
+		productOptionId, err := s.upsertProductOption(ex, productId, optionId, productOption)
+		if err != nil {
+			return fmt.Errorf("product option %s: option %s: %v", uid, productOption.OptionUid, err)
+		}
+		keepOptions = append(keepOptions, productOptionId)
+
+		for _, value := range productOption.Values {
+			optionValueId, err := s.getOptionValueByUID(ex, optionId, value.OptionValueUid)
+			if err != nil {
+				return fmt.Errorf("product option %s: value search: %v", uid, err)
+			}
+			if optionValueId == 0 {
+				return fmt.Errorf("product option %s: option %s value %s %w",
+					uid, productOption.OptionUid, value.OptionValueUid, entity.ErrNotFound)
+			}
+
+			productOptionValueId, err := s.upsertProductOptionValue(ex, productId, optionId, productOptionId, optionValueId, value)
+			if err != nil {
+				return fmt.Errorf("product option %s: value %s: %v", uid, value.OptionValueUid, err)
+			}
+			keepValues = append(keepValues, productOptionValueId)
+		}
+	}
+
+	if err = s.deleteProductRowsExcept(ex, "product_option_value", "product_option_value_id", productId, keepValues); err != nil {
+		return fmt.Errorf("product option %s: cleanup values: %v", uid, err)
+	}
+	if err = s.deleteProductRowsExcept(ex, "product_option", "product_option_id", productId, keepOptions); err != nil {
+		return fmt.Errorf("product option %s: cleanup options: %v", uid, err)
+	}
+	return nil
+}
+
+// upsertProductOption creates or updates the product_option row for a product and option, returns its ID.
+func (s *MySql) upsertProductOption(ex executor, productId, optionId int64, productOption *entity.ProductOption) (int64, error) {
+	required := 0
+	if productOption.Required {
+		required = 1
+	}
+
+	query := fmt.Sprintf(`SELECT product_option_id FROM %sproduct_option WHERE product_id=? AND option_id=? LIMIT 1`, s.prefix)
+	var productOptionId int64
+	err := ex.QueryRow(query, productId, optionId).Scan(&productOptionId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	if productOptionId == 0 {
+		userData := map[string]interface{}{
+			"product_id": productId,
+			"option_id":  optionId,
+			"value":      productOption.Value,
+			"required":   required,
+		}
+		return s.insert(ex, "product_option", userData)
+	}
+
+	err = s.update(ex, "product_option",
+		map[string]interface{}{"value": productOption.Value, "required": required},
+		"product_option_id=?", productOptionId)
+	if err != nil {
+		return 0, err
+	}
+	return productOptionId, nil
+}
+
+// upsertProductOptionValue creates or updates the product_option_value row for a product option and value, returns its ID.
+func (s *MySql) upsertProductOptionValue(ex executor, productId, optionId, productOptionId, optionValueId int64, value *entity.ProductOptionValue) (int64, error) {
+	query := fmt.Sprintf(`SELECT product_option_value_id FROM %sproduct_option_value WHERE product_option_id=? AND option_value_id=? LIMIT 1`, s.prefix)
+	var productOptionValueId int64
+	err := ex.QueryRow(query, productOptionId, optionValueId).Scan(&productOptionValueId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	userData := map[string]interface{}{
+		"quantity":      value.Quantity,
+		"subtract":      value.SubtractFlag(),
+		"price":         math.Abs(value.Price),
+		"price_prefix":  value.PricePrefix(),
+		"weight":        math.Abs(value.Weight),
+		"weight_prefix": value.WeightPrefix(),
+	}
+
+	if productOptionValueId == 0 {
+		userData["product_option_id"] = productOptionId
+		userData["product_id"] = productId
+		userData["option_id"] = optionId
+		userData["option_value_id"] = optionValueId
+		userData["points_prefix"] = "+"
+		return s.insert(ex, "product_option_value", userData)
+	}
+
+	err = s.update(ex, "product_option_value", userData, "product_option_value_id=?", productOptionValueId)
+	if err != nil {
+		return 0, err
+	}
+	return productOptionValueId, nil
+}
+
+// deleteProductRowsExcept removes rows of a product-owned table whose ID column is not in keep.
+func (s *MySql) deleteProductRowsExcept(ex executor, table, idColumn string, productId int64, keep []int64) error {
+	if len(keep) == 0 {
+		query := fmt.Sprintf(`DELETE FROM %s%s WHERE product_id=?`, s.prefix, table)
+		_, err := ex.Exec(query, productId)
+		return err
+	}
+
+	placeholders := make([]string, len(keep))
+	args := make([]interface{}, 0, 1+len(keep))
+	args = append(args, productId)
+	for i, id := range keep {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s%s WHERE product_id=? AND %s NOT IN (%s)`,
+		s.prefix, table, idColumn, strings.Join(placeholders, ","))
+	_, err := ex.Exec(query, args...)
+	return err
+}
+
+// upsertRow updates the row identified by the key columns or inserts a new one with key and data combined.
+func (s *MySql) upsertRow(ex executor, table string, key, data map[string]interface{}) error {
+	conditions := make([]string, 0, len(key))
+	args := make([]interface{}, 0, len(key))
+	for column, value := range key {
+		conditions = append(conditions, fmt.Sprintf("%s=?", column))
+		args = append(args, value)
+	}
+	where := strings.Join(conditions, " AND ")
+
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s%s WHERE %s`, s.prefix, table, where)
+	var count int
+	if err := ex.QueryRow(query, args...).Scan(&count); err != nil {
+		return fmt.Errorf("lookup: %v", err)
+	}
+
+	if count > 0 {
+		return s.update(ex, table, data, where, args...)
+	}
+
+	userData := make(map[string]interface{}, len(key)+len(data))
+	for column, value := range key {
+		userData[column] = value
+	}
+	for column, value := range data {
+		userData[column] = value
+	}
+	_, err := s.insert(ex, table, userData)
+	return err
+}
+
+// getOptionByUID returns the option_id for a given option UID, or 0 if not found.
+func (s *MySql) getOptionByUID(ex executor, uid string) (int64, error) {
+	stmt, err := s.stmtSelectOptionId(ex)
+	if err != nil {
+		return 0, err
+	}
+
+	var optionId int64
+	err = stmt.QueryRow(uid).Scan(&optionId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return optionId, nil
+}
+
+// getOptionValueByUID returns the option_value_id for a value UID within the option, or 0 if not found.
+func (s *MySql) getOptionValueByUID(ex executor, optionId int64, uid string) (int64, error) {
+	stmt, err := s.stmtSelectOptionValueId(ex)
+	if err != nil {
+		return 0, err
+	}
+
+	var optionValueId int64
+	err = stmt.QueryRow(optionId, uid).Scan(&optionValueId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return optionValueId, nil
+}
@@ -0,0 +1,325 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"ocapi/entity"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProductSearch returns the stored state of a product identified by its UID together with
+// the requested sub-resources (see entity.ParseProductInclude).
+func (s *MySql) ProductSearch(uid string, include map[string]bool) (*entity.ProductView, error) {
+	product, err := s.readProduct(s.db, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	readers := []struct {
+		name string
+		read func(ex executor, product *entity.ProductView) error
+	}{
+		{entity.IncludeDescriptions, s.readProductDescriptions},
+		{entity.IncludeCategories, s.readProductCategories},
+		{entity.IncludeAttributes, s.readProductAttributes},
+		{entity.IncludeImages, s.readProductImages},
+		{entity.IncludeSpecials, s.readProductSpecials},
+		{entity.IncludeDiscounts, s.readProductDiscounts},
+		{entity.IncludeCustomFields, s.readProductCustomFields},
+	}
+	for _, reader := range readers {
+		if !include[reader.name] {
+			continue
+		}
+		if err = reader.read(s.db, product); err != nil {
+			return nil, fmt.Errorf("product %s: %s: %v", uid, reader.name, err)
+		}
+	}
+	return product, nil
+}
+
+// productViewColumns are the core product fields read into entity.ProductView by scanProductView.
+const productViewColumns = `p.product_id, p.product_uid, p.model, p.price, p.quantity, p.stock_status_id,
+				COALESCE(m.name, ''), COALESCE(m.manufacturer_uid, ''), p.status, p.weight, p.weight_class_id,
+				p.length, p.width, p.height, p.length_class_id, COALESCE(p.image, ''),
+				p.image_uid, p.date_added, p.date_modified`
+
+// scanProductView scans a row selected with productViewColumns.
+func scanProductView(row interface {
+	Scan(dest ...interface{}) error
+}) (*entity.ProductView, error) {
+	var product entity.ProductView
+	var status int
+	err := row.Scan(
+		&product.ProductId,
+		&product.Uid,
+		&product.Article,
+		&product.Price,
+		&product.Quantity,
+		&product.StockStatusId,
+		&product.Manufacturer,
+		&product.ManufacturerUid,
+		&status,
+		&product.Weight,
+		&product.WeightClassId,
+		&product.Length,
+		&product.Width,
+		&product.Height,
+		&product.LengthClassId,
+		&product.Image,
+		&product.ImageUid,
+		&product.DateAdded,
+		&product.DateModified,
+	)
+	if err != nil {
+		return nil, err
+	}
+	product.Active = status == 1
+	return &product, nil
+}
+
+// readProduct reads the core fields of a product.
+func (s *MySql) readProduct(ex executor, uid string) (*entity.ProductView, error) {
+	query := fmt.Sprintf(`SELECT %s
+			FROM %sproduct p
+			LEFT JOIN %smanufacturer m ON m.manufacturer_id = p.manufacturer_id
+			WHERE p.product_uid = ? LIMIT 1`, productViewColumns, s.prefix, s.prefix)
+
+	product, err := scanProductView(ex.QueryRow(query, uid))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("product %s %w", uid, entity.ErrNotFound)
+		}
+		return nil, fmt.Errorf("product search: %v", err)
+	}
+	return product, nil
+}
+
+// ProductChanges returns up to limit products modified at or after since, ordered by date_modified
+// and product_id, starting after the cursor position if given.
+func (s *MySql) ProductChanges(since time.Time, cursor *entity.ProductCursor, limit int) ([]*entity.ProductView, error) {
+	where := "p.date_modified >= ?"
+	args := []interface{}{since}
+	if cursor != nil {
+		where += " AND (p.date_modified > ? OR (p.date_modified = ? AND p.product_id > ?))"
+		args = append(args, cursor.DateModified, cursor.DateModified, cursor.ProductId)
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`SELECT %s
+			FROM %sproduct p
+			LEFT JOIN %smanufacturer m ON m.manufacturer_id = p.manufacturer_id
+			WHERE %s
+			ORDER BY p.date_modified, p.product_id
+			LIMIT ?`, productViewColumns, s.prefix, s.prefix, where)
+
+	products := make([]*entity.ProductView, 0, limit)
+	err := queryRows(s.db, query, args, func(rows *sql.Rows) error {
+		product, err := scanProductView(rows)
+		if err != nil {
+			return err
+		}
+		products = append(products, product)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("product changes: %v", err)
+	}
+	return products, nil
+}
+
+// readProductDescriptions reads descriptions in all languages, including SEO keywords.
+func (s *MySql) readProductDescriptions(ex executor, product *entity.ProductView) error {
+	query := fmt.Sprintf(`SELECT language_id, name, description, meta_title, meta_description, meta_keyword, tag
+			FROM %sproduct_description WHERE product_id = ? ORDER BY language_id`, s.prefix)
+
+	err := queryRows(ex, query, []interface{}{product.ProductId}, func(rows *sql.Rows) error {
+		d := &entity.ProductDescription{ProductUid: product.Uid}
+		if err := rows.Scan(&d.LanguageId, &d.Name, &d.Description, &d.MetaTitle, &d.MetaDescription, &d.MetaKeyword, &d.Tag); err != nil {
+			return err
+		}
+		d.Language = s.languageCode(d.LanguageId)
+		product.Descriptions = append(product.Descriptions, d)
+		return nil
+	})
+	if err != nil || s.seoLayout == seoNone {
+		return err
+	}
+
+	// the keyword of the default store
+	for _, d := range product.Descriptions {
+		if d.SeoKeyword, err = s.findSeoKeyword(ex, "product_id", product.ProductId, 0, d.LanguageId); err != nil {
+			return fmt.Errorf("seo keyword: %v", err)
+		}
+	}
+	return nil
+}
+
+// readProductCategories reads UIDs of the categories the product is linked to.
+func (s *MySql) readProductCategories(ex executor, product *entity.ProductView) error {
+	query := fmt.Sprintf(`SELECT c.category_uid FROM %sproduct_to_category pc
+			JOIN %scategory c ON c.category_id = pc.category_id
+			WHERE pc.product_id = ? ORDER BY c.category_uid`, s.prefix, s.prefix)
+
+	return queryRows(ex, query, []interface{}{product.ProductId}, func(rows *sql.Rows) error {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return err
+		}
+		product.Categories = append(product.Categories, uid)
+		return nil
+	})
+}
+
+// readProductAttributes reads attribute values with attribute UIDs.
+func (s *MySql) readProductAttributes(ex executor, product *entity.ProductView) error {
+	query := fmt.Sprintf(`SELECT a.attribute_uid, pa.language_id, pa.text FROM %sproduct_attribute pa
+			JOIN %sattribute a ON a.attribute_id = pa.attribute_id
+			WHERE pa.product_id = ? ORDER BY a.attribute_uid, pa.language_id`, s.prefix, s.prefix)
+
+	return queryRows(ex, query, []interface{}{product.ProductId}, func(rows *sql.Rows) error {
+		a := &entity.ProductAttribute{ProductUid: product.Uid}
+		if err := rows.Scan(&a.AttributeUid, &a.LanguageId, &a.Text); err != nil {
+			return err
+		}
+		a.Language = s.languageCode(a.LanguageId)
+		product.Attributes = append(product.Attributes, a)
+		return nil
+	})
+}
+
+// readProductImages reads the main image followed by additional images in sort order.
+func (s *MySql) readProductImages(ex executor, product *entity.ProductView) error {
+	if product.Image != "" {
+		product.Images = append(product.Images, &entity.ProductImageView{
+			FileUid: mainImageUid(product.Image, product.ImageUid),
+			Image:   product.Image,
+			IsMain:  true,
+		})
+	}
+
+	query := fmt.Sprintf(`SELECT file_uid, image, sort_order FROM %sproduct_image
+			WHERE product_id = ? ORDER BY sort_order, product_image_id`, s.prefix)
+
+	return queryRows(ex, query, []interface{}{product.ProductId}, func(rows *sql.Rows) error {
+		i := &entity.ProductImageView{}
+		var image sql.NullString
+		if err := rows.Scan(&i.FileUid, &image, &i.SortOrder); err != nil {
+			return err
+		}
+		i.Image = image.String
+		product.Images = append(product.Images, i)
+		return nil
+	})
+}
+
+// mainImageUid returns the file UID of a main image: the image_uid column, or for images linked
+// before it was kept, the file name without extension.
+func mainImageUid(image, imageUid string) string {
+	if imageUid != "" {
+		return imageUid
+	}
+	base := filepath.Base(image)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// readProductSpecials reads special prices of all customer groups.
+func (s *MySql) readProductSpecials(ex executor, product *entity.ProductView) error {
+	query := fmt.Sprintf(`SELECT customer_group_id, price, priority, date_start, date_end FROM %sproduct_special
+			WHERE product_id = ? ORDER BY customer_group_id, priority`, s.prefix)
+
+	return queryRows(ex, query, []interface{}{product.ProductId}, func(rows *sql.Rows) error {
+		sp := &entity.ProductSpecial{ProductUid: product.Uid}
+		var dateStart, dateEnd sql.NullTime
+		if err := rows.Scan(&sp.GroupId, &sp.Price, &sp.Priority, &dateStart, &dateEnd); err != nil {
+			return err
+		}
+		sp.DateStart, sp.DateEnd = nullTime(dateStart), nullTime(dateEnd)
+		product.Specials = append(product.Specials, sp)
+		return nil
+	})
+}
+
+// readProductDiscounts reads quantity discount tiers of all customer groups.
+func (s *MySql) readProductDiscounts(ex executor, product *entity.ProductView) error {
+	query := fmt.Sprintf(`SELECT customer_group_id, quantity, price, priority, date_start, date_end FROM %sproduct_discount
+			WHERE product_id = ? ORDER BY customer_group_id, quantity, priority`, s.prefix)
+
+	return queryRows(ex, query, []interface{}{product.ProductId}, func(rows *sql.Rows) error {
+		d := &entity.ProductDiscount{ProductUid: product.Uid}
+		var dateStart, dateEnd sql.NullTime
+		if err := rows.Scan(&d.GroupId, &d.Quantity, &d.Price, &d.Priority, &dateStart, &dateEnd); err != nil {
+			return err
+		}
+		d.DateStart, d.DateEnd = nullTime(dateStart), nullTime(dateEnd)
+		product.Discounts = append(product.Discounts, d)
+		return nil
+	})
+}
+
+// readProductCustomFields reads values of the allowed custom fields present in the product table.
+func (s *MySql) readProductCustomFields(ex executor, product *entity.ProductView) error {
+	columns, err := s.readStructure("product")
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(s.customFields))
+	for name := range s.customFields {
+		if _, ok := columns[name]; ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	query := fmt.Sprintf("SELECT `%s` FROM %sproduct WHERE product_id = ?", strings.Join(names, "`, `"), s.prefix)
+	values := make([]sql.NullString, len(names))
+	dest := make([]interface{}, len(names))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err = ex.QueryRow(query, product.ProductId).Scan(dest...); err != nil {
+		return err
+	}
+
+	for i, name := range names {
+		product.CustomFields = append(product.CustomFields, &entity.CustomField{
+			FieldName:  name,
+			FieldValue: values[i].String,
+		})
+	}
+	return nil
+}
+
+// queryRows runs the query and calls scan for every row.
+func queryRows(ex executor, query string, args []interface{}, scan func(rows *sql.Rows) error) error {
+	rows, err := ex.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	for rows.Next() {
+		if err = scan(rows); err != nil {
+			return fmt.Errorf("scan: %v", err)
+		}
+	}
+	return rows.Err()
+}
+
+// nullTime returns the time value or zero time for NULL.
+func nullTime(t sql.NullTime) time.Time {
+	if t.Valid {
+		return t.Time
+	}
+	return time.Time{}
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"ocapi/entity"
+)
+
+// referenceList describes how a reference dictionary is read. The items query returns id, code, name,
+// url, country_id, value and status of each entry; the optional names query returns id, language_id,
+// name and code of the localized names. Queries take the table prefix and the name column of the items.
+// When table is set and has no name column (OpenCart 4.1), the names are read from its description table.
+type referenceList struct {
+	table string
+	items string
+	names string
+}
+
+var referenceLists = map[string]referenceList{
+	"stores": {
+		items: "SELECT 0, '', COALESCE((SELECT value FROM %[1]ssetting WHERE store_id = 0 AND `key` = 'config_name' LIMIT 1), ''), '', 0, 0, NULL" +
+			" UNION ALL SELECT store_id, '', name, url, 0, 0, NULL FROM %[1]sstore ORDER BY 1",
+	},
+	"languages": {
+		items: "SELECT language_id, code, name, '', 0, 0, status FROM %[1]slanguage ORDER BY sort_order, language_id",
+	},
+	"customer_groups": {
+		items: "SELECT customer_group_id, '', '', '', 0, 0, NULL FROM %[1]scustomer_group ORDER BY sort_order, customer_group_id",
+		names: "SELECT customer_group_id, language_id, name, '' FROM %[1]scustomer_group_description",
+	},
+	"order_statuses": {
+		items: "SELECT DISTINCT order_status_id, '', '', '', 0, 0, NULL FROM %[1]sorder_status ORDER BY order_status_id",
+		names: "SELECT order_status_id, language_id, name, '' FROM %[1]sorder_status",
+	},
+	"stock_statuses": {
+		items: "SELECT DISTINCT stock_status_id, '', '', '', 0, 0, NULL FROM %[1]sstock_status ORDER BY stock_status_id",
+		names: "SELECT stock_status_id, language_id, name, '' FROM %[1]sstock_status",
+	},
+	"tax_classes": {
+		items: "SELECT tax_class_id, '', title, '', 0, 0, NULL FROM %[1]stax_class ORDER BY tax_class_id",
+	},
+	"weight_classes": {
+		items: "SELECT weight_class_id, '', '', '', 0, value, NULL FROM %[1]sweight_class ORDER BY weight_class_id",
+		names: "SELECT weight_class_id, language_id, title, unit FROM %[1]sweight_class_description",
+	},
+	"length_classes": {
+		items: "SELECT length_class_id, '', '', '', 0, value, NULL FROM %[1]slength_class ORDER BY length_class_id",
+		names: "SELECT length_class_id, language_id, title, unit FROM %[1]slength_class_description",
+	},
+	"countries": {
+		table: "country",
+		items: "SELECT country_id, iso_code_2, %[2]s, '', 0, 0, status FROM %[1]scountry ORDER BY country_id",
+	},
+	"zones": {
+		table: "zone",
+		items: "SELECT zone_id, code, %[2]s, '', country_id, 0, status FROM %[1]szone ORDER BY country_id, zone_id",
+	},
+}
+
+// Dictionary reads the reference list with the given name; ErrNotFound if there is no such list.
+func (s *MySql) Dictionary(name string) ([]*entity.DictionaryItem, error) {
+	list, ok := referenceLists[name]
+	if !ok {
+		return nil, fmt.Errorf("dictionary %s: %w", name, entity.ErrNotFound)
+	}
+
+	nameColumn := "name"
+	if list.table != "" {
+		columns, err := s.readStructure(list.table)
+		if err != nil {
+			return nil, fmt.Errorf("dictionary %s: %v", name, err)
+		}
+		if _, ok = columns["name"]; !ok {
+			nameColumn = "''"
+			list.names = fmt.Sprintf("SELECT %[1]s_id, language_id, name, '' FROM %%[1]s%[1]s_description", list.table)
+		}
+	}
+
+	var items []*entity.DictionaryItem
+	itemIds := make(map[int64]*entity.DictionaryItem)
+	err := queryRows(s.db, fmt.Sprintf(list.items, s.prefix, nameColumn), nil, func(rows *sql.Rows) error {
+		item := &entity.DictionaryItem{}
+		var status sql.NullBool
+		if err := rows.Scan(&item.Id, &item.Code, &item.Name, &item.Url, &item.CountryId, &item.Value, &status); err != nil {
+			return err
+		}
+		item.Status = status.Bool
+		items = append(items, item)
+		itemIds[item.Id] = item
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dictionary %s: %v", name, err)
+	}
+
+	if list.names != "" {
+		if err = s.localizeItems(fmt.Sprintf(list.names, s.prefix), itemIds); err != nil {
+			return nil, fmt.Errorf("dictionary %s: names: %v", name, err)
+		}
+	}
+	if name == "stores" {
+		for code, id := range s.storeCodes {
+			if item := itemIds[id]; item != nil && (item.Code == "" || code < item.Code) {
+				item.Code = code
+			}
+		}
+	}
+	return items, nil
+}
+
+// localizeItems sets the names of the items by language code. An empty name and code of an item
+// are taken from the first shop language that has them.
+func (s *MySql) localizeItems(query string, items map[int64]*entity.DictionaryItem) error {
+	codes := make(map[int64]map[string]string)
+	err := queryRows(s.db, query, nil, func(rows *sql.Rows) error {
+		var id, languageId int64
+		var name, code string
+		if err := rows.Scan(&id, &languageId, &name, &code); err != nil {
+			return err
+		}
+		item := items[id]
+		language := s.languageCode(languageId)
+		if item == nil || language == "" {
+			return nil
+		}
+		if item.Names == nil {
+			item.Names = make(map[string]string)
+			codes[id] = make(map[string]string)
+		}
+		item.Names[language] = name
+		codes[id][language] = code
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	languages := s.Languages()
+	for id, item := range items {
+		for _, language := range languages {
+			if item.Name == "" {
+				item.Name = item.Names[language.Code]
+			}
+			if item.Code == "" {
+				item.Code = codes[id][language.Code]
+			}
+		}
+	}
+	return nil
+}
+
+// ReloadDictionaries re-reads the shop languages and forgets the cached IDs of entries referenced by name,
+// so that changes made in the OpenCart admin take effect.
+func (s *MySql) ReloadDictionaries() error {
+	if err := s.loadLanguages(); err != nil {
+		return fmt.Errorf("load languages: %w", err)
+	}
+	s.dictionaryMu.Lock()
+	s.dictionaryIds = make(map[string]int64)
+	s.dictionaryMu.Unlock()
+	return nil
+}
@@ -0,0 +1,238 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"ocapi/internal/lib/slug"
+	"strconv"
+	"strings"
+)
+
+// seoLayout identifies how the OpenCart version in use stores SEO keywords.
+type seoLayout int
+
+const (
+	seoNone        seoLayout = iota // no SEO table found, keywords are ignored
+	seoUrlAlias                     // url_alias: query, keyword (OpenCart 2 and older)
+	seoUrlQuery                     // seo_url: store_id, language_id, query, keyword (OpenCart 3)
+	seoUrlKeyValue                  // seo_url: store_id, language_id, key, value, keyword (OpenCart 4)
+)
+
+// detectSeoLayout inspects the schema and returns the SEO keyword table layout.
+func (s *MySql) detectSeoLayout() (seoLayout, error) {
+	columns, err := s.readStructure("seo_url")
+	if err != nil {
+		return seoNone, err
+	}
+	if _, ok := columns["key"]; ok {
+		return seoUrlKeyValue, nil
+	}
+	if _, ok := columns["query"]; ok {
+		return seoUrlQuery, nil
+	}
+
+	columns, err = s.readStructure("url_alias")
+	if err != nil {
+		return seoNone, err
+	}
+	if _, ok := columns["query"]; ok {
+		return seoUrlAlias, nil
+	}
+	return seoNone, nil
+}
+
+// seoTable returns the SEO table name for the detected layout.
+func (s *MySql) seoTable() string {
+	if s.seoLayout == seoUrlAlias {
+		return "url_alias"
+	}
+	return "seo_url"
+}
+
+// seoEntity builds the condition selecting the SEO rows of an entity, e.g. product_id=42,
+// together with the column values used when inserting a new row.
+func (s *MySql) seoEntity(entityKey string, id int64) (string, []interface{}, map[string]interface{}) {
+	value := strconv.FormatInt(id, 10)
+	if s.seoLayout == seoUrlKeyValue {
+		return "`key`=? AND `value`=?", []interface{}{entityKey, value},
+			map[string]interface{}{"key": entityKey, "value": value}
+	}
+	query := entityKey + "=" + value
+	return "query=?", []interface{}{query}, map[string]interface{}{"query": query}
+}
+
+// seoStores returns the stores the entity is linked to in its _to_store table, e.g. product_to_store
+// for product_id. url_alias has no store column, a single keyword is written there.
+func (s *MySql) seoStores(ex executor, entityKey string, id int64) ([]int64, error) {
+	if s.seoLayout == seoUrlAlias {
+		return []int64{0}, nil
+	}
+
+	query := fmt.Sprintf("SELECT store_id FROM %s%s_to_store WHERE %s=?", s.prefix, strings.TrimSuffix(entityKey, "_id"), entityKey)
+	rows, err := ex.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	var stores []int64
+	for rows.Next() {
+		var storeId int64
+		if err = rows.Scan(&storeId); err != nil {
+			return nil, err
+		}
+		stores = append(stores, storeId)
+	}
+	return stores, rows.Err()
+}
+
+// seoScope builds the store and language condition of a keyword. url_alias has no scope;
+// OpenCart 3 requires keywords to be unique per store, OpenCart 4 per store and language.
+func (s *MySql) seoScope(storeId, languageId int64, unique bool) (string, []interface{}) {
+	switch s.seoLayout {
+	case seoUrlAlias:
+		return "1=1", nil
+	case seoUrlQuery:
+		if unique {
+			return "store_id=?", []interface{}{storeId}
+		}
+	}
+	return "store_id=? AND language_id=?", []interface{}{storeId, languageId}
+}
+
+// findSeoKeyword returns the current keyword of an entity in the given store and language, empty if not set.
+func (s *MySql) findSeoKeyword(ex executor, entityKey string, id, storeId, languageId int64) (string, error) {
+	entityCond, entityArgs, _ := s.seoEntity(entityKey, id)
+	scopeCond, scopeArgs := s.seoScope(storeId, languageId, false)
+
+	query := fmt.Sprintf("SELECT keyword FROM %s%s WHERE %s AND %s LIMIT 1", s.prefix, s.seoTable(), entityCond, scopeCond)
+	var keyword string
+	err := ex.QueryRow(query, append(entityArgs, scopeArgs...)...).Scan(&keyword)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return keyword, nil
+}
+
+// uniqueSeoKeyword returns the keyword, suffixed with -2, -3, ... if it is already used by another entity.
+func (s *MySql) uniqueSeoKeyword(ex executor, keyword, entityKey string, id, storeId, languageId int64) (string, error) {
+	entityCond, entityArgs, _ := s.seoEntity(entityKey, id)
+	scopeCond, scopeArgs := s.seoScope(storeId, languageId, true)
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s WHERE keyword=? AND %s AND NOT (%s)", s.prefix, s.seoTable(), scopeCond, entityCond)
+	candidate := keyword
+	for i := 2; ; i++ {
+		args := append([]interface{}{candidate}, scopeArgs...)
+		args = append(args, entityArgs...)
+
+		var count int
+		if err := ex.QueryRow(query, args...).Scan(&count); err != nil {
+			return "", fmt.Errorf("keyword lookup: %v", err)
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", keyword, i)
+	}
+}
+
+// saveSeoKeyword writes the SEO keyword of a product or category in the given language, in every store
+// the entity is linked to. Without a keyword, and with transliteration enabled, a slug is generated from
+// the name, but only where the entity has no keyword yet. Keywords taken by other entities are suffixed.
+func (s *MySql) saveSeoKeyword(ex executor, entityKey string, id, languageId int64, keyword, name string) error {
+	if s.seoLayout == seoNone {
+		return nil
+	}
+
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" && !s.seoTransliterate {
+		return nil
+	}
+
+	stores, err := s.seoStores(ex, entityKey, id)
+	if err != nil {
+		return fmt.Errorf("seo keyword stores: %v", err)
+	}
+	for _, storeId := range stores {
+		if err = s.saveStoreSeoKeyword(ex, entityKey, id, storeId, languageId, keyword, name); err != nil {
+			return fmt.Errorf("store %d: %v", storeId, err)
+		}
+	}
+	return nil
+}
+
+// saveStoreSeoKeyword writes the SEO keyword of an entity in one store and language.
+func (s *MySql) saveStoreSeoKeyword(ex executor, entityKey string, id, storeId, languageId int64, keyword, name string) error {
+	if keyword == "" {
+		current, err := s.findSeoKeyword(ex, entityKey, id, storeId, languageId)
+		if err != nil {
+			return fmt.Errorf("seo keyword lookup: %v", err)
+		}
+		if current != "" {
+			return nil
+		}
+		keyword = slug.Make(name)
+		if keyword == "" {
+			return nil
+		}
+	}
+
+	keyword, err := s.uniqueSeoKeyword(ex, keyword, entityKey, id, storeId, languageId)
+	if err != nil {
+		return fmt.Errorf("seo keyword: %v", err)
+	}
+
+	entityCond, entityArgs, entityData := s.seoEntity(entityKey, id)
+	scopeCond, scopeArgs := s.seoScope(storeId, languageId, false)
+	where := entityCond + " AND " + scopeCond
+	args := append(entityArgs, scopeArgs...)
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s%s WHERE %s", s.prefix, s.seoTable(), where)
+	var count int
+	if err = ex.QueryRow(query, args...).Scan(&count); err != nil {
+		return fmt.Errorf("seo keyword lookup: %v", err)
+	}
+
+	if count > 0 {
+		query = fmt.Sprintf("UPDATE %s%s SET keyword=? WHERE %s", s.prefix, s.seoTable(), where)
+		if _, err = ex.Exec(query, append([]interface{}{keyword}, args...)...); err != nil {
+			return fmt.Errorf("seo keyword update: %v", err)
+		}
+		return nil
+	}
+
+	userData := map[string]interface{}{
+		"keyword": keyword,
+	}
+	for column, value := range entityData {
+		userData[column] = value
+	}
+	if s.seoLayout != seoUrlAlias {
+		userData["store_id"] = storeId
+		userData["language_id"] = languageId
+	}
+	if _, err = s.insert(ex, s.seoTable(), userData); err != nil {
+		return fmt.Errorf("seo keyword insert: %v", err)
+	}
+	return nil
+}
+
+// deleteSeoKeywords removes the SEO keywords of a product or category in all stores and languages.
+func (s *MySql) deleteSeoKeywords(ex executor, entityKey string, id int64) error {
+	if s.seoLayout == seoNone {
+		return nil
+	}
+
+	entityCond, entityArgs, _ := s.seoEntity(entityKey, id)
+	query := fmt.Sprintf("DELETE FROM %s%s WHERE %s", s.prefix, s.seoTable(), entityCond)
+	if _, err := ex.Exec(query, entityArgs...); err != nil {
+		return fmt.Errorf("seo keyword delete: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"ocapi/entity"
+)
+
+// ReplaceProductSpecials replaces the special prices of a batch of products. The request is the
+// complete set for each product it mentions: a product may have several dated specials per customer
+// group, existing rows are deleted and the new ones inserted inside one transaction per product.
+// Specials of the same group and priority with intersecting date ranges are rejected.
+// Results are reported per product.
+func (s *MySql) ReplaceProductSpecials(specials []*entity.ProductSpecial, report bool) ([]*entity.ItemResult, error) {
+	groups := make(map[string][]*entity.ProductSpecial)
+	order := make([]string, 0)
+	for _, special := range specials {
+		if _, ok := groups[special.ProductUid]; !ok {
+			order = append(order, special.ProductUid)
+		}
+		groups[special.ProductUid] = append(groups[special.ProductUid], special)
+	}
+
+	return saveItems(order, report,
+		func(uid string) string { return uid },
+		func(uid string) error {
+			if err := checkSpecialOverlap(groups[uid]); err != nil {
+				return fmt.Errorf("product special %s: %w", uid, err)
+			}
+			return s.withTx(func(tx *sql.Tx) error {
+				return s.replaceProductSpecials(tx, uid, groups[uid])
+			})
+		})
+}
+
+// checkSpecialOverlap returns an error if any two specials of the set overlap.
+func checkSpecialOverlap(specials []*entity.ProductSpecial) error {
+	for i := 0; i < len(specials); i++ {
+		for j := i + 1; j < len(specials); j++ {
+			if specials[i].Overlaps(specials[j]) {
+				return fmt.Errorf("specials for group %d priority %d overlap: %w",
+					specials[i].GroupId, specials[i].Priority, entity.ErrInvalid)
+			}
+		}
+	}
+	return nil
+}
+
+// replaceProductSpecials deletes all special rows of the product and inserts the given ones.
+func (s *MySql) replaceProductSpecials(ex executor, uid string, specials []*entity.ProductSpecial) error {
+	productId, err := s.getProductByUID(ex, uid)
+	if err != nil {
+		return fmt.Errorf("product special %s: product search: %v", uid, err)
+	}
+	if productId == 0 {
+		return fmt.Errorf("product special %s: product %w", uid, entity.ErrNotFound)
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %sproduct_special WHERE product_id=?`, s.prefix)
+	if _, err = ex.Exec(query, productId); err != nil {
+		return fmt.Errorf("product special %s: delete: %v", uid, err)
+	}
+
+	for _, special := range specials {
+		specialData := map[string]interface{}{
+			"product_id":        productId,
+			"customer_group_id": special.GroupId,
+			"price":             special.Price,
+			"date_start":        special.DateStart,
+			"date_end":          special.DateEnd,
+			"priority":          special.Priority,
+		}
+		if _, err = s.insert(ex, "product_special", specialData); err != nil {
+			return fmt.Errorf("product special %s: %v", uid, err)
+		}
+	}
+	return nil
+}
+
+// PurgeExpiredSpecials deletes special prices that ended before today and returns the number of
+// removed rows; specials without an end date (0000-00-00) are kept.
+func (s *MySql) PurgeExpiredSpecials() (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %sproduct_special WHERE date_end > '1000-01-01' AND date_end < CURDATE()`, s.prefix)
+	result, err := s.db.Exec(query)
+	if err != nil {
+		return 0, fmt.Errorf("purge specials: %v", err)
+	}
+	return result.RowsAffected()
+}
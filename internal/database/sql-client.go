@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"ocapi/entity"
 	"ocapi/internal/config"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -21,6 +22,24 @@ type MySql struct {
 	statements   map[string]*sql.Stmt
 	mu           sync.Mutex
 	customFields map[string]bool // allowed custom field names for products
+	txPerRequest bool            // wrap the whole product request in one transaction instead of one per product
+
+	manufacturerStrict bool // product manufacturer is an existing manufacturer UID, not a name
+
+	defaultStores []int64          // stores new products and categories are published to
+	storeCodes    map[string]int64 // store codes accepted instead of store IDs
+
+	languages     []*entity.Language          // shop languages, reloaded along with dictionaries
+	languageIds   map[int64]*entity.Language  // languages by ID
+	languageCodes map[string]*entity.Language // languages by lower-case code
+	languageMu    sync.RWMutex
+
+	defaults      productDefaults  // column values of new products
+	dictionaryIds map[string]int64 // cached IDs of dictionary entries referenced by name
+	dictionaryMu  sync.Mutex
+
+	seoLayout        seoLayout // SEO keyword table layout of the OpenCart version
+	seoTransliterate bool      // generate SEO keywords from names when not supplied
 }
 
 // NewSQLClient creates a new MySQL client, establishes the connection, configures the pool,
@@ -66,12 +85,27 @@ func NewSQLClient(conf *config.Config) (*MySql, error) {
 		customFields[field] = true
 	}
 
+	// Products and categories are published to the default store unless configured otherwise
+	defaultStores := conf.Store.Default
+	if len(defaultStores) == 0 {
+		defaultStores = []int64{0}
+	}
+
 	sdb := &MySql{
 		db:           db,
 		prefix:       conf.SQL.Prefix,
 		structure:    make(map[string]map[string]Column),
 		statements:   make(map[string]*sql.Stmt),
 		customFields: customFields,
+		txPerRequest: conf.Product.Transaction == "request",
+
+		manufacturerStrict: conf.Product.ManufacturerStrict,
+
+		defaultStores: defaultStores,
+		storeCodes:    conf.Store.Codes,
+		dictionaryIds: make(map[string]int64),
+
+		seoTransliterate: conf.Seo.Transliterate,
 	}
 
 	if err = sdb.addColumnIfNotExists("product", "batch_uid", "VARCHAR(64) NOT NULL"); err != nil {
@@ -92,6 +126,45 @@ func NewSQLClient(conf *config.Config) (*MySql, error) {
 	if err = sdb.addColumnIfNotExists("product_image", "file_uid", "VARCHAR(64) NOT NULL"); err != nil {
 		return nil, err
 	}
+	if err = sdb.addColumnIfNotExists("option", "option_uid", "VARCHAR(64) NOT NULL"); err != nil {
+		return nil, err
+	}
+	if err = sdb.addColumnIfNotExists("option_value", "option_value_uid", "VARCHAR(64) NOT NULL"); err != nil {
+		return nil, err
+	}
+	if err = sdb.addColumnIfNotExists("download", "download_uid", "VARCHAR(64) NOT NULL"); err != nil {
+		return nil, err
+	}
+	if err = sdb.addColumnIfNotExists("manufacturer", "manufacturer_uid", "VARCHAR(64) NOT NULL"); err != nil {
+		return nil, err
+	}
+	if err = sdb.addColumnIfNotExists("product", "image_hash", "VARCHAR(64) NOT NULL"); err != nil {
+		return nil, err
+	}
+	if err = sdb.addColumnIfNotExists("product_image", "file_hash", "VARCHAR(64) NOT NULL"); err != nil {
+		return nil, err
+	}
+	if err = sdb.addColumnIfNotExists("product", "image_uid", "VARCHAR(64) NOT NULL"); err != nil {
+		return nil, err
+	}
+	if err = sdb.addColumnIfNotExists("product", "image_version", "VARCHAR(64) NOT NULL"); err != nil {
+		return nil, err
+	}
+	if err = sdb.addColumnIfNotExists("product_image", "file_version", "VARCHAR(64) NOT NULL"); err != nil {
+		return nil, err
+	}
+
+	if sdb.defaults, err = sdb.loadProductDefaults(conf); err != nil {
+		return nil, fmt.Errorf("product defaults: %w", err)
+	}
+
+	if sdb.seoLayout, err = sdb.detectSeoLayout(); err != nil {
+		return nil, fmt.Errorf("detect seo table: %w", err)
+	}
+
+	if err = sdb.loadLanguages(); err != nil {
+		return nil, fmt.Errorf("load languages: %w", err)
+	}
 
 	return sdb, nil
 }
@@ -113,143 +186,250 @@ func (s *MySql) Stats() string {
 		len(s.structure))
 }
 
-// ProductSearch returns all product table columns for a product identified by its UID.
-func (s *MySql) ProductSearch(uid string) (interface{}, error) {
-	return s.ReadTable(
-		fmt.Sprintf("%sproduct", s.prefix),
-		fmt.Sprintf("product_uid='%s'", uid),
-		0,
-		false)
+// saveItems applies save to every item of a bulk request. Without report the first error
+// aborts processing and is returned; with report all items are processed and the outcome
+// of each one is collected by its UID.
+func saveItems[T any](items []T, report bool, uid func(T) string, save func(T) error) ([]*entity.ItemResult, error) {
+	var results []*entity.ItemResult
+	if report {
+		results = make([]*entity.ItemResult, 0, len(items))
+	}
+	for _, item := range items {
+		err := save(item)
+		if report {
+			results = append(results, entity.NewItemResult(uid(item), err))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
 }
 
 // SaveProducts upserts a batch of products: creates new ones or updates existing by UID.
-func (s *MySql) SaveProducts(productsData []*entity.ProductData) error {
-	for _, productData := range productsData {
-		productId, err := s.getProductByUID(productData.Uid)
-		if err != nil {
-			return fmt.Errorf("product search: %v", err)
-		}
+// Each product is written inside its own transaction, or the whole batch inside a single
+// one when the transaction scope is configured as "request"; any error rolls back the scope.
+// With report enabled every product gets its own transaction and an outcome in the results.
+func (s *MySql) SaveProducts(productsData []*entity.ProductData, report bool) ([]*entity.ItemResult, error) {
+	if s.txPerRequest && !report {
+		return nil, s.withTx(func(tx *sql.Tx) error {
+			for _, productData := range productsData {
+				if err := s.saveProduct(tx, productData); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
 
-		if productId == 0 {
-			err = s.addProduct(productData)
-		} else {
-			err = s.updateProduct(productId, productData)
-		}
+	return saveItems(productsData, report,
+		func(productData *entity.ProductData) string { return productData.Uid },
+		func(productData *entity.ProductData) error {
+			return s.withTx(func(tx *sql.Tx) error {
+				return s.saveProduct(tx, productData)
+			})
+		})
+}
 
-		if err != nil {
-			return fmt.Errorf("product %s: %v", productData.Uid, err)
-		}
+// saveProduct creates a new product or updates the existing one by UID using the given executor.
+func (s *MySql) saveProduct(ex executor, productData *entity.ProductData) error {
+	productId, err := s.getProductByUID(ex, productData.Uid)
+	if err != nil {
+		return fmt.Errorf("product search: %v", err)
+	}
+
+	if productId == 0 {
+		err = s.addProduct(ex, productData)
+	} else {
+		err = s.updateProduct(ex, productId, productData)
+	}
+
+	if err != nil {
+		return fmt.Errorf("product %s: %w", productData.Uid, err)
 	}
 	return nil
 }
 
 // SaveProductsDescription upserts descriptions for a batch of products identified by UID.
-func (s *MySql) SaveProductsDescription(productsDescData []*entity.ProductDescription) error {
-	for _, productDescData := range productsDescData {
+func (s *MySql) SaveProductsDescription(productsDescData []*entity.ProductDescription, report bool) ([]*entity.ItemResult, error) {
+	return saveItems(productsDescData, report,
+		func(productDescData *entity.ProductDescription) string { return productDescData.ProductUid },
+		s.saveProductDescription)
+}
 
-		productId, err := s.getProductByUID(productDescData.ProductUid)
-		if err != nil {
-			return fmt.Errorf("product search: %v", err)
-		}
+// saveProductDescription upserts a single product description.
+func (s *MySql) saveProductDescription(productDescData *entity.ProductDescription) error {
+	productId, err := s.getProductByUID(s.db, productDescData.ProductUid)
+	if err != nil {
+		return fmt.Errorf("product search: %v", err)
+	}
 
-		if productId == 0 {
-			return fmt.Errorf("product decription: uid %s not found", productDescData.ProductUid)
-		}
+	if productId == 0 {
+		return fmt.Errorf("product decription: uid %s %w", productDescData.ProductUid, entity.ErrNotFound)
+	}
 
-		err = s.upsertProductDescription(productId, productDescData)
-		if err != nil {
-			return fmt.Errorf("product description %s: %v", productDescData.ProductUid, err)
-		}
+	productDescData.LanguageId, err = s.resolveLanguage(productDescData.LanguageId, productDescData.Language)
+	if err != nil {
+		return fmt.Errorf("product description %s: %w", productDescData.ProductUid, err)
+	}
+
+	err = s.upsertProductDescription(productId, productDescData)
+	if err != nil {
+		return fmt.Errorf("product description %s: %v", productDescData.ProductUid, err)
+	}
+
+	err = s.saveSeoKeyword(s.db, "product_id", productId, productDescData.LanguageId, productDescData.SeoKeyword, productDescData.Name)
+	if err != nil {
+		return fmt.Errorf("product description %s: %v", productDescData.ProductUid, err)
 	}
 	return nil
 }
 
 // SaveProductSpecial upserts special price records for a batch of products.
-func (s *MySql) SaveProductSpecial(products []*entity.ProductSpecial) error {
-	for _, special := range products {
+func (s *MySql) SaveProductSpecial(products []*entity.ProductSpecial, report bool) ([]*entity.ItemResult, error) {
+	return saveItems(products, report,
+		func(special *entity.ProductSpecial) string { return special.ProductUid },
+		s.saveProductSpecial)
+}
 
-		productId, err := s.getProductByUID(special.ProductUid)
-		if err != nil {
-			return fmt.Errorf("product search: %v", err)
-		}
+// saveProductSpecial upserts a single special price record.
+func (s *MySql) saveProductSpecial(special *entity.ProductSpecial) error {
+	productId, err := s.getProductByUID(s.db, special.ProductUid)
+	if err != nil {
+		return fmt.Errorf("product search: %v", err)
+	}
 
-		if productId == 0 {
-			return fmt.Errorf("product special: uid %s not found", special.ProductUid)
-		}
+	if productId == 0 {
+		return fmt.Errorf("product special: uid %s %w", special.ProductUid, entity.ErrNotFound)
+	}
 
-		err = s.upsertProductSpecial(productId, special)
-		if err != nil {
-			return fmt.Errorf("product special %s: %v", special.ProductUid, err)
-		}
+	err = s.upsertProductSpecial(productId, special)
+	if err != nil {
+		return fmt.Errorf("product special %s: %v", special.ProductUid, err)
 	}
 	return nil
 }
 
 // SaveCategories upserts a batch of categories, resolving parent UIDs to IDs.
-func (s *MySql) SaveCategories(categoriesData []*entity.CategoryData) error {
-	for _, categoryData := range categoriesData {
-		categoryId, err := s.getCategoryByUID(categoryData.CategoryUID)
+func (s *MySql) SaveCategories(categoriesData []*entity.CategoryData, report bool) ([]*entity.ItemResult, error) {
+	return saveItems(categoriesData, report,
+		func(categoryData *entity.CategoryData) string { return categoryData.CategoryUID },
+		s.saveCategory)
+}
+
+// saveCategory upserts a single category with its path and store links in one transaction.
+func (s *MySql) saveCategory(categoryData *entity.CategoryData) error {
+	stores, err := s.resolveStores(categoryData.Stores, categoryData.StoreCodes)
+	if err != nil {
+		return fmt.Errorf("category %s: %w", categoryData.CategoryUID, err)
+	}
+
+	return s.withTx(func(tx *sql.Tx) error {
+		categoryId, err := s.getCategoryByUID(tx, categoryData.CategoryUID)
 		if err != nil {
 			return fmt.Errorf("category search: %s %v", categoryData.CategoryUID, err)
 		}
-		parentId, err := s.getCategoryByUID(categoryData.ParentUID)
+		parentId, err := s.getCategoryByUID(tx, categoryData.ParentUID)
 		if err != nil {
 			return fmt.Errorf("parent search: %s %v", categoryData.ParentUID, err)
 		}
 
+		// the parent chain is read with locks, so a concurrent save cannot move an ancestor under this category
+		path, err := s.categoryAncestors(tx, parentId)
+		if err != nil {
+			return fmt.Errorf("category [%d] %s: parent: %w", categoryId, categoryData.CategoryUID, err)
+		}
+		if slices.Contains(path, categoryId) {
+			return fmt.Errorf("category [%d] %s: parent %s is its descendant: %w",
+				categoryId, categoryData.CategoryUID, categoryData.ParentUID, entity.ErrInvalid)
+		}
+
 		category := entity.CategoryFromCategoryData(categoryData)
 		category.CategoryId = categoryId
 		category.ParentId = parentId
 
-		err = s.updateCategory(category)
-
-		if err != nil {
+		if err = s.updateCategory(tx, category); err != nil {
 			return fmt.Errorf("category [%d] %s: %v", categoryId, categoryData.CategoryUID, err)
 		}
-	}
-	return nil
+		if err = s.updateCategoryPaths(tx, categoryId, append(path, categoryId)); err != nil {
+			return fmt.Errorf("category [%d] %s: %v", categoryId, categoryData.CategoryUID, err)
+		}
+		if stores != nil {
+			if err = s.setCategoryStores(tx, categoryId, stores); err != nil {
+				return fmt.Errorf("category [%d] %s: %v", categoryId, categoryData.CategoryUID, err)
+			}
+		}
+		return nil
+	})
 }
 
 // SaveCategoriesDescription upserts descriptions for a batch of categories.
-func (s *MySql) SaveCategoriesDescription(categoriesDescData []*entity.CategoryDescriptionData) error {
-	for _, categoryDescData := range categoriesDescData {
-		categoryId, err := s.getCategoryByUID(categoryDescData.CategoryUid)
-		if err != nil {
-			return fmt.Errorf("category search: %v", err)
-		}
-		category := entity.CategoryDescriptionFromCategoryDescriptionData(categoryDescData)
-		category.CategoryId = categoryId
-		err = s.upsertCategoryDescription(category)
+func (s *MySql) SaveCategoriesDescription(categoriesDescData []*entity.CategoryDescriptionData, report bool) ([]*entity.ItemResult, error) {
+	return saveItems(categoriesDescData, report,
+		func(categoryDescData *entity.CategoryDescriptionData) string { return categoryDescData.CategoryUid },
+		s.saveCategoryDescription)
+}
 
-		if err != nil {
-			return fmt.Errorf("category [%d] %s: %v", categoryId, categoryDescData.CategoryUid, err)
-		}
+// saveCategoryDescription upserts a single category description.
+func (s *MySql) saveCategoryDescription(categoryDescData *entity.CategoryDescriptionData) error {
+	categoryId, err := s.getCategoryByUID(s.db, categoryDescData.CategoryUid)
+	if err != nil {
+		return fmt.Errorf("category search: %v", err)
+	}
+	categoryDescData.LanguageId, err = s.resolveLanguage(categoryDescData.LanguageId, categoryDescData.Language)
+	if err != nil {
+		return fmt.Errorf("category %s: %w", categoryDescData.CategoryUid, err)
+	}
+	category := entity.CategoryDescriptionFromCategoryDescriptionData(categoryDescData)
+	category.CategoryId = categoryId
+
+	err = s.upsertCategoryDescription(category)
+	if err != nil {
+		return fmt.Errorf("category [%d] %s: %w", categoryId, categoryDescData.CategoryUid, err)
+	}
+
+	err = s.saveSeoKeyword(s.db, "category_id", categoryId, categoryDescData.LanguageId, categoryDescData.SeoKeyword, categoryDescData.Name)
+	if err != nil {
+		return fmt.Errorf("category [%d] %s: %v", categoryId, categoryDescData.CategoryUid, err)
 	}
 	return nil
 }
 
 // SaveAttributes upserts a batch of attributes and their descriptions.
-func (s *MySql) SaveAttributes(attributes []*entity.Attribute) error {
-	for _, attribute := range attributes {
-		attributeId, err := s.getAttributeByUID(attribute.Uid)
+func (s *MySql) SaveAttributes(attributes []*entity.Attribute, report bool) ([]*entity.ItemResult, error) {
+	return saveItems(attributes, report,
+		func(attribute *entity.Attribute) string { return attribute.Uid },
+		s.saveAttribute)
+}
+
+// saveAttribute upserts a single attribute with its descriptions.
+func (s *MySql) saveAttribute(attribute *entity.Attribute) error {
+	for _, attributeDesc := range attribute.Descriptions {
+		languageId, err := s.resolveLanguage(attributeDesc.LanguageId, attributeDesc.Language)
 		if err != nil {
-			return fmt.Errorf("attribute search: %v", err)
+			return fmt.Errorf("attribute %s: %w", attribute.Uid, err)
 		}
+		attributeDesc.LanguageId = languageId
+	}
 
-		if attributeId == 0 {
-			attributeId, err = s.addAttribute(attribute)
-		} else {
-			err = s.updateAttribute(attributeId, attribute)
-		}
+	attributeId, err := s.getAttributeByUID(attribute.Uid)
+	if err != nil {
+		return fmt.Errorf("attribute search: %v", err)
+	}
 
-		for _, attributeDesc := range attribute.Descriptions {
-			if err = s.upsertAttributeDescription(attributeId, attributeDesc); err != nil {
-				return fmt.Errorf("description: %v", err)
-			}
-		}
+	if attributeId == 0 {
+		attributeId, err = s.addAttribute(attribute)
+	} else {
+		err = s.updateAttribute(attributeId, attribute)
+	}
+	if err != nil {
+		return fmt.Errorf("attribute %s: %v", attribute.Uid, err)
+	}
 
-		if err != nil {
-			return fmt.Errorf("attribute %s: %v", attribute.Uid, err)
+	for _, attributeDesc := range attribute.Descriptions {
+		if err = s.upsertAttributeDescription(attributeId, attributeDesc); err != nil {
+			return fmt.Errorf("attribute %s: description: %v", attribute.Uid, err)
 		}
 	}
 	return nil
@@ -264,8 +444,8 @@ type productAttributeKey struct {
 // SaveProductAttributes synchronises attribute values for a batch of product-attribute
 // pairs. The request is treated as the complete attribute set for each product: existing
 // values are updated, new ones inserted, and any attribute rows for that product that are
-// not present in the request (across all languages) are removed.
-func (s *MySql) SaveProductAttributes(productAttributes []*entity.ProductAttribute) error {
+// not present in the request (across all languages) are removed. Results are reported per product.
+func (s *MySql) SaveProductAttributes(productAttributes []*entity.ProductAttribute, report bool) ([]*entity.ItemResult, error) {
 	// Group the flat request list by product so the full set is known before deleting.
 	groups := make(map[string][]*entity.ProductAttribute)
 	order := make([]string, 0)
@@ -276,34 +456,48 @@ func (s *MySql) SaveProductAttributes(productAttributes []*entity.ProductAttribu
 		groups[productAttribute.ProductUid] = append(groups[productAttribute.ProductUid], productAttribute)
 	}
 
-	for _, uid := range order {
-		productId, err := s.getProductByUID(uid)
+	return saveItems(order, report,
+		func(uid string) string { return uid },
+		func(uid string) error {
+			return s.saveProductAttributes(uid, groups[uid])
+		})
+}
+
+// saveProductAttributes replaces the attribute set of a single product.
+func (s *MySql) saveProductAttributes(uid string, productAttributes []*entity.ProductAttribute) error {
+	productId, err := s.getProductByUID(s.db, uid)
+	if err != nil {
+		return fmt.Errorf("product attribute %s: product search: %v", uid, err)
+	}
+	if productId == 0 {
+		return fmt.Errorf("product attribute %s: product %w", uid, entity.ErrNotFound)
+	}
+
+	for _, productAttribute := range productAttributes {
+		productAttribute.LanguageId, err = s.resolveLanguage(productAttribute.LanguageId, productAttribute.Language)
 		if err != nil {
-			return fmt.Errorf("product attribute %s: product search: %v", uid, err)
+			return fmt.Errorf("product attribute %s: %w", uid, err)
 		}
-		if productId == 0 {
-			return fmt.Errorf("product attribute %s: product not found", uid)
-		}
-
-		keep := make([]productAttributeKey, 0, len(groups[uid]))
-		for _, productAttribute := range groups[uid] {
-			attributeId, err := s.getAttributeByUID(productAttribute.AttributeUid)
-			if err != nil {
-				return fmt.Errorf("product attribute %s: attribute search: %v", uid, err)
-			}
-			if attributeId == 0 {
-				return fmt.Errorf("product attribute %s: attribute %s not found", uid, productAttribute.AttributeUid)
-			}
+	}
 
-			if err := s.upsertProductAttribute(productId, attributeId, productAttribute.LanguageId, productAttribute.Text); err != nil {
-				return fmt.Errorf("product attribute %s: %v", uid, err)
-			}
-			keep = append(keep, productAttributeKey{attributeId: attributeId, languageId: productAttribute.LanguageId})
+	keep := make([]productAttributeKey, 0, len(productAttributes))
+	for _, productAttribute := range productAttributes {
+		attributeId, err := s.getAttributeByUID(productAttribute.AttributeUid)
+		if err != nil {
+			return fmt.Errorf("product attribute %s: attribute search: %v", uid, err)
+		}
+		if attributeId == 0 {
+			return fmt.Errorf("product attribute %s: attribute %s %w", uid, productAttribute.AttributeUid, entity.ErrNotFound)
 		}
 
-		if err := s.deleteProductAttributesExcept(productId, keep); err != nil {
-			return fmt.Errorf("product attribute %s: cleanup: %v", uid, err)
+		if err := s.upsertProductAttribute(productId, attributeId, productAttribute.LanguageId, productAttribute.Text); err != nil {
+			return fmt.Errorf("product attribute %s: %v", uid, err)
 		}
+		keep = append(keep, productAttributeKey{attributeId: attributeId, languageId: productAttribute.LanguageId})
+	}
+
+	if err := s.deleteProductAttributesExcept(productId, keep); err != nil {
+		return fmt.Errorf("product attribute %s: cleanup: %v", uid, err)
 	}
 	return nil
 }
@@ -345,11 +539,11 @@ func (s *MySql) UpdateProductImage(imageData *entity.ProductImageData) error {
 
 // updateMainProductImage sets the main image URL on the product record.
 func (s *MySql) updateMainProductImage(imageData *entity.ProductImageData) error {
-	stmt, err := s.stmtUpdateProductImage()
+	stmt, err := s.stmtUpdateProductImage(s.db)
 	if err != nil {
 		return err
 	}
-	_, err = stmt.Exec(imageData.ImageUrl, imageData.ProductUid)
+	_, err = stmt.Exec(imageData.ImageUrl, imageData.FileHash, imageData.FileUid, imageData.Version, imageData.ProductUid)
 	if err != nil {
 		return fmt.Errorf("update: %v", err)
 	}
@@ -357,9 +551,9 @@ func (s *MySql) updateMainProductImage(imageData *entity.ProductImageData) error
 }
 
 // updateProductImage inserts or updates an additional (non-main) product image in the product_image table.
-// If the image with the given file_uid does not exist, it inserts a new row; otherwise updates sort_order.
+// If the image with the given file_uid does not exist, it inserts a new row; otherwise updates the path, hash, version and sort_order.
 func (s *MySql) updateProductImage(imageData *entity.ProductImageData) error {
-	productId, err := s.getProductByUID(imageData.ProductUid)
+	productId, err := s.getProductByUID(s.db, imageData.ProductUid)
 	if err != nil {
 		return err
 	}
@@ -368,7 +562,7 @@ func (s *MySql) updateProductImage(imageData *entity.ProductImageData) error {
 		return fmt.Errorf("no product found: %s", imageData.ProductUid)
 	}
 
-	stmt, err := s.stmtGetProductNotMainImage()
+	stmt, err := s.stmtGetProductNotMainImage(s.db)
 	if err != nil {
 		return err
 	}
@@ -379,23 +573,25 @@ func (s *MySql) updateProductImage(imageData *entity.ProductImageData) error {
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			userData := map[string]interface{}{
-				"product_id": productId,
-				"file_uid":   imageData.FileUid,
-				"image":      imageData.ImageUrl,
-				"sort_order": imageData.SortOrder,
+				"product_id":   productId,
+				"file_uid":     imageData.FileUid,
+				"file_hash":    imageData.FileHash,
+				"file_version": imageData.Version,
+				"image":        imageData.ImageUrl,
+				"sort_order":   imageData.SortOrder,
 			}
 
-			_, err = s.insert("product_image", userData)
+			_, err = s.insert(s.db, "product_image", userData)
 		}
 		return err
 	}
 
-	// update sort order if the image is already in DB
-	state, err := s.stmtUpdateProductImageAdd()
+	// update the image if it is already in DB
+	state, err := s.stmtUpdateProductImageAdd(s.db)
 	if err != nil {
 		return err
 	}
-	_, err = state.Exec(imageData.SortOrder, productImageId)
+	_, err = state.Exec(imageData.ImageUrl, imageData.FileHash, imageData.Version, imageData.SortOrder, productImageId)
 
 	return err
 }
@@ -405,7 +601,7 @@ func (s *MySql) updateProductImage(imageData *entity.ProductImageData) error {
 // exist in the database (and were kept), so the caller can determine which UIDs are new.
 func (s *MySql) CleanUpProductImages(productUid string, images []string) (map[string]bool, error) {
 	// 1) Get the product ID by UID
-	productId, err := s.getProductByUID(productUid)
+	productId, err := s.getProductByUID(s.db, productUid)
 	if err != nil {
 		return nil, err
 	}
@@ -414,7 +610,7 @@ func (s *MySql) CleanUpProductImages(productUid string, images []string) (map[st
 	}
 
 	// 2) Query existing (product_image_id, file_uid) pairs from the database
-	stmt, err := s.stmtGetProductImages()
+	stmt, err := s.stmtGetProductImages(s.db)
 	if err != nil {
 		return nil, err
 	}
@@ -487,27 +683,31 @@ func (s *MySql) CleanUpProductImages(productUid string, images []string) (map[st
 	return seen, nil
 }
 
-// GetProductMainImage returns the main image path from the product table for the given product UID.
-func (s *MySql) GetProductMainImage(productUid string) (string, error) {
-	stmt, err := s.stmtGetProductMainImage()
+// GetProductMainImageUid returns the file UID of the main image of the product, empty if it has none.
+func (s *MySql) GetProductMainImageUid(productUid string) (string, error) {
+	stmt, err := s.stmtGetProductMainImage(s.db)
 	if err != nil {
 		return "", err
 	}
 
 	var image sql.NullString
-	err = stmt.QueryRow(productUid).Scan(&image)
+	var imageUid string
+	err = stmt.QueryRow(productUid).Scan(&image, &imageUid)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", nil
 		}
 		return "", fmt.Errorf("select main image: %v", err)
 	}
-	return image.String, nil
+	if image.String == "" {
+		return "", nil
+	}
+	return mainImageUid(image.String, imageUid), nil
 }
 
 // InsertProductImage adds a new additional image row into the product_image table for the given product.
 func (s *MySql) InsertProductImage(productUid string, fileUid string, imageUrl string, sortOrder int) error {
-	productId, err := s.getProductByUID(productUid)
+	productId, err := s.getProductByUID(s.db, productUid)
 	if err != nil {
 		return err
 	}
@@ -522,10 +722,135 @@ func (s *MySql) InsertProductImage(productUid string, fileUid string, imageUrl s
 		"sort_order": sortOrder,
 	}
 
-	_, err = s.insert("product_image", userData)
+	_, err = s.insert(s.db, "product_image", userData)
 	return err
 }
 
+// FindImageByHash returns the path of a stored product image with the given content hash, empty if none.
+func (s *MySql) FindImageByHash(hash string) (string, error) {
+	query := fmt.Sprintf(`SELECT image FROM %sproduct WHERE image_hash=?
+			UNION SELECT image FROM %sproduct_image WHERE file_hash=? LIMIT 1`, s.prefix, s.prefix)
+	var image sql.NullString
+	err := s.db.QueryRow(query, hash, hash).Scan(&image)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return image.String, nil
+}
+
+// FindImageByUid returns the path of a stored product image with the given file UID, empty if none.
+func (s *MySql) FindImageByUid(fileUid string) (string, error) {
+	query := fmt.Sprintf(`SELECT image FROM %sproduct WHERE image_uid=?
+			UNION SELECT image FROM %sproduct_image WHERE file_uid=? LIMIT 1`, s.prefix, s.prefix)
+	var image sql.NullString
+	err := s.db.QueryRow(query, fileUid, fileUid).Scan(&image)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return image.String, nil
+}
+
+// ImageFileUids returns the file UIDs of the product images stored in the file with the given path.
+func (s *MySql) ImageFileUids(image string) ([]string, error) {
+	query := fmt.Sprintf(`SELECT image_uid FROM %sproduct WHERE image=? AND image_uid<>''
+			UNION SELECT file_uid FROM %sproduct_image WHERE image=?`, s.prefix, s.prefix)
+	var uids []string
+	err := queryRows(s.db, query, []interface{}{image, image}, func(rows *sql.Rows) error {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return err
+		}
+		uids = append(uids, uid)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uids, nil
+}
+
+// RelinkImage points the product images stored in the file image to the file target,
+// except the images of the given file UID, in one transaction.
+func (s *MySql) RelinkImage(image, target, fileUid string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		query := fmt.Sprintf(`UPDATE %sproduct SET image=? WHERE image=? AND image_uid NOT IN (?, '')`, s.prefix)
+		if _, err := tx.Exec(query, target, image, fileUid); err != nil {
+			return fmt.Errorf("relink main images: %v", err)
+		}
+		query = fmt.Sprintf(`UPDATE %sproduct_image SET image=? WHERE image=? AND file_uid<>?`, s.prefix)
+		if _, err := tx.Exec(query, target, image, fileUid); err != nil {
+			return fmt.Errorf("relink images: %v", err)
+		}
+		return nil
+	})
+}
+
+// ProductImageVersion returns the stored path and version of a product image with the given file UID,
+// the main image or an additional one; empty values if the product has no such image.
+func (s *MySql) ProductImageVersion(productUid, fileUid string, isMain bool) (*entity.StoredImage, error) {
+	query := fmt.Sprintf(`SELECT image, image_version FROM %sproduct WHERE product_uid=? AND image_uid=? LIMIT 1`, s.prefix)
+	if !isMain {
+		query = fmt.Sprintf(`SELECT pi.image, pi.file_version FROM %sproduct_image pi
+				JOIN %sproduct p ON p.product_id = pi.product_id
+				WHERE p.product_uid=? AND pi.file_uid=? LIMIT 1`, s.prefix, s.prefix)
+	}
+
+	var image sql.NullString
+	stored := &entity.StoredImage{}
+	err := s.db.QueryRow(query, productUid, fileUid).Scan(&image, &stored.Version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return stored, nil
+		}
+		return nil, err
+	}
+	stored.Image = image.String
+	return stored, nil
+}
+
+// ImageVersions returns stored images of all products by file UID, main and additional ones.
+func (s *MySql) ImageVersions(fileUids []string) (map[string][]*entity.StoredImage, error) {
+	versions := make(map[string][]*entity.StoredImage, len(fileUids))
+	if len(fileUids) == 0 {
+		return versions, nil
+	}
+
+	placeholders := make([]string, len(fileUids))
+	args := make([]interface{}, 0, 2*len(fileUids))
+	for i, uid := range fileUids {
+		placeholders[i] = "?"
+		args = append(args, uid)
+	}
+	args = append(args, args...)
+	in := strings.Join(placeholders, ",")
+
+	query := fmt.Sprintf(`SELECT image_uid, image, image_version FROM %sproduct WHERE image_uid IN (%s)
+			UNION ALL SELECT file_uid, image, file_version FROM %sproduct_image WHERE file_uid IN (%s)`,
+		s.prefix, in, s.prefix, in)
+	err := queryRows(s.db, query, args, func(rows *sql.Rows) error {
+		var uid string
+		var image sql.NullString
+		stored := &entity.StoredImage{}
+		if err := rows.Scan(&uid, &image, &stored.Version); err != nil {
+			return err
+		}
+		stored.Image = image.String
+		versions[uid] = append(versions[uid], stored)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetAllImages returns all image paths from the product and product_image tables (used for orphan cleanup).
 // GetAllImages returns all image paths from the product and product_image tables (used for orphan cleanup).
 func (s *MySql) GetAllImages() ([]string, error) {
 	query := fmt.Sprintf(`SELECT image FROM %sproduct UNION SELECT image FROM %sproduct_image`, s.prefix, s.prefix)
@@ -555,7 +880,7 @@ func (s *MySql) GetAllImages() ([]string, error) {
 // CheckApiKey looks up an API key in the database and returns the associated username.
 func (s *MySql) CheckApiKey(key string) (string, error) {
 
-	stmt, err := s.stmtGetApiUsername()
+	stmt, err := s.stmtGetApiUsername(s.db)
 	if err != nil {
 		return "", err
 	}
@@ -571,13 +896,32 @@ func (s *MySql) CheckApiKey(key string) (string, error) {
 }
 
 // updateProduct updates an existing product record, its category links, and custom fields.
-func (s *MySql) updateProduct(productId int64, productData *entity.ProductData) error {
-	manufacturerId, err := s.getManufacturerId(productData.Manufacturer)
+// Store links are synchronised only when the request lists stores.
+func (s *MySql) updateProduct(ex executor, productId int64, productData *entity.ProductData) error {
+	stores, err := s.resolveStores(productData.Stores, productData.StoreCodes)
+	if err != nil {
+		return err
+	}
+
+	manufacturerId, err := s.getManufacturerId(ex, productData.Manufacturer, stores)
 	if err != nil {
-		return fmt.Errorf("manufacturer search: %v", err)
+		return fmt.Errorf("manufacturer search: %w", err)
 	}
 
-	stmt, err := s.stmtUpdateProduct()
+	stockStatusId, err := s.stockStatusId(ex, productData)
+	if err != nil {
+		return err
+	}
+	weightClassId, err := s.weightClassId(ex, productData)
+	if err != nil {
+		return err
+	}
+	overrides, err := s.productOverrides(ex, productData)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := s.stmtUpdateProduct(ex)
 	if err != nil {
 		return err
 	}
@@ -591,12 +935,12 @@ func (s *MySql) updateProduct(productId int64, productData *entity.ProductData)
 		//productData.Mpn,
 		//productData.Location,
 		productData.Quantity,
-		productData.StockStatusID(),
+		stockStatusId,
 		productData.Price,
 		manufacturerId,
 		productData.Status(),
 		productData.Weight,
-		productData.WeightClassId,
+		weightClassId,
 		time.Now(),
 		productData.BatchUid,
 		productId)
@@ -604,12 +948,24 @@ func (s *MySql) updateProduct(productId int64, productData *entity.ProductData)
 		return fmt.Errorf("update: %v", err)
 	}
 
-	err = s.setProductCategories(productId, productData.Categories)
+	if len(overrides) > 0 {
+		if err = s.update(ex, "product", overrides, "product_id=?", productId); err != nil {
+			return fmt.Errorf("update defaults: %v", err)
+		}
+	}
+
+	err = s.setProductCategories(ex, productId, productData.Categories)
 	if err != nil {
 		return err
 	}
 
-	err = s.updateCustomFields(productId, productData)
+	if stores != nil {
+		if err = s.setProductStores(ex, productId, stores); err != nil {
+			return err
+		}
+	}
+
+	err = s.updateCustomFields(ex, productId, productData)
 	if err != nil {
 		return err
 	}
@@ -618,17 +974,17 @@ func (s *MySql) updateProduct(productId int64, productData *entity.ProductData)
 }
 
 // updateCustomFields applies whitelisted custom field updates to a product record.
-func (s *MySql) updateCustomFields(productId int64, productData *entity.ProductData) error {
+func (s *MySql) updateCustomFields(ex executor, productId int64, productData *entity.ProductData) error {
 	if len(productData.CustomFields) > 0 {
 		for _, field := range productData.CustomFields {
 			// Validate field name against whitelist (defaults + configured)
 			if !s.customFields[field.FieldName] {
-				return fmt.Errorf("custom field not allowed: %s", field.FieldName)
+				return fmt.Errorf("custom field %s not allowed: %w", field.FieldName, entity.ErrInvalid)
 			}
 			data := map[string]interface{}{
 				field.FieldName: field.FieldValue,
 			}
-			err := s.update("product", data, "product_id=?", productId)
+			err := s.update(ex, "product", data, "product_id=?", productId)
 			if err != nil {
 				return fmt.Errorf("update custom field %s: %v", field.FieldName, err)
 			}
@@ -638,23 +994,23 @@ func (s *MySql) updateCustomFields(productId int64, productData *entity.ProductD
 }
 
 // setProductCategories replaces all category associations for a product with the given category UIDs.
-func (s *MySql) setProductCategories(productId int64, categories []string) error {
+func (s *MySql) setProductCategories(ex executor, productId int64, categories []string) error {
 	if len(categories) == 0 {
 		return nil
 	}
 	query := fmt.Sprintf(`DELETE FROM %sproduct_to_category WHERE product_id=?`, s.prefix)
-	_, err := s.db.Exec(query, productId)
+	_, err := ex.Exec(query, productId)
 	if err != nil {
 		return fmt.Errorf("delete: %v", err)
 	}
 
 	for _, categoryUID := range categories {
-		categoryId, err := s.getCategoryByUID(categoryUID)
+		categoryId, err := s.getCategoryByUID(ex, categoryUID)
 		if err != nil {
 			return fmt.Errorf("category search: %v", err)
 		}
 
-		if err = s.addProductToCategory(productId, categoryId); err != nil {
+		if err = s.addProductToCategory(ex, productId, categoryId); err != nil {
 			return fmt.Errorf("add to category: %v", err)
 		}
 	}
@@ -663,12 +1019,12 @@ func (s *MySql) setProductCategories(productId int64, categories []string) error
 }
 
 // addProductToCategory adds a product to a category with an INSERT statement
-func (s *MySql) addProductToCategory(productId, categoryId int64) error {
+func (s *MySql) addProductToCategory(ex executor, productId, categoryId int64) error {
 	query := fmt.Sprintf(`INSERT INTO %sproduct_to_category (
                         product_id,
                         category_id)
 			VALUES (?, ?)`, s.prefix)
-	_, err := s.db.Exec(query, productId, categoryId)
+	_, err := ex.Exec(query, productId, categoryId)
 	if err != nil {
 		return fmt.Errorf("insert: %v", err)
 	}
@@ -676,10 +1032,32 @@ func (s *MySql) addProductToCategory(productId, categoryId int64) error {
 }
 
 // addProduct inserts a new product record along with its store, layout, category, and custom field associations.
-func (s *MySql) addProduct(product *entity.ProductData) error {
-	manufacturerId, err := s.getManufacturerId(product.Manufacturer)
+// Without stores in the request the product is published to the configured default stores.
+func (s *MySql) addProduct(ex executor, product *entity.ProductData) error {
+	stores, err := s.resolveStores(product.Stores, product.StoreCodes)
+	if err != nil {
+		return err
+	}
+	if stores == nil {
+		stores = s.defaultStores
+	}
+
+	manufacturerId, err := s.getManufacturerId(ex, product.Manufacturer, stores)
 	if err != nil {
-		return fmt.Errorf("manufacturer search: %v", err)
+		return fmt.Errorf("manufacturer search: %w", err)
+	}
+
+	stockStatusId, err := s.stockStatusId(ex, product)
+	if err != nil {
+		return err
+	}
+	weightClassId, err := s.weightClassId(ex, product)
+	if err != nil {
+		return err
+	}
+	overrides, err := s.productOverrides(ex, product)
+	if err != nil {
+		return err
 	}
 
 	userData := map[string]interface{}{
@@ -696,99 +1074,37 @@ func (s *MySql) addProduct(product *entity.ProductData) error {
 		"manufacturer_id": manufacturerId,
 		"quantity":        product.Quantity,
 		"status":          product.Status(),
-		"stock_status_id": product.StockStatusID(),
-		"minimum":         1,
-		"subtract":        1,
-		"date_available":  time.Now().AddDate(0, 0, -3),
-		"shipping":        1,
-		"tax_class_id":    9,
+		"stock_status_id": stockStatusId,
 		"weight":          product.Weight,
-		"weight_class_id": product.WeightClassId,
-		"length_class_id": 0,
+		"weight_class_id": weightClassId,
 		"date_added":      time.Now(),
 		"date_modified":   time.Now(),
 		"batch_uid":       product.BatchUid,
 	}
-
-	productId, err := s.insert("product", userData)
-	if err != nil {
-		return err
+	for column, value := range s.defaultProductFields() {
+		userData[column] = value
 	}
-
-	if err = s.addProductToStore(productId); err != nil {
-		return err
+	for column, value := range overrides {
+		userData[column] = value
 	}
 
-	err = s.setProductCategories(productId, product.Categories)
+	productId, err := s.insert(ex, "product", userData)
 	if err != nil {
-		return fmt.Errorf("set categories: %v", err)
-	}
-
-	if err = s.addProductToLayout(productId); err != nil {
 		return err
 	}
 
-	err = s.updateCustomFields(productId, product)
-	if err != nil {
+	if err = s.setProductStores(ex, productId, stores); err != nil {
 		return err
 	}
 
-	return nil
-}
-
-// addProductToStore links a product to the default store (store_id=0).
-func (s *MySql) addProductToStore(productID int64) error {
-	query := fmt.Sprintf(
-		`INSERT INTO %sproduct_to_store (
-				product_id,
-				store_id)
-			VALUES (?, ?)`,
-		s.prefix)
-
-	_, err := s.db.Exec(query,
-		productID, 0)
-
+	err = s.setProductCategories(ex, productId, product.Categories)
 	if err != nil {
-		return fmt.Errorf("product to store: %v", err)
-	}
-
-	return nil
-}
-
-// addCategoryToStore links a category to the default store (store_id=0).
-func (s *MySql) addCategoryToStore(categoryID int64) error {
-	query := fmt.Sprintf(
-		`INSERT INTO %scategory_to_store (
-				category_id,
-				store_id)
-			VALUES (?, ?)`,
-		s.prefix)
-
-	_, err := s.db.Exec(query,
-		categoryID, 0)
-
-	if err != nil {
-		return fmt.Errorf("category to store: %v", err)
+		return fmt.Errorf("set categories: %v", err)
 	}
 
-	return nil
-}
-
-// addProductToLayout links a product to the default layout (store_id=0, layout_id=0).
-func (s *MySql) addProductToLayout(productID int64) error {
-	query := fmt.Sprintf(
-		`INSERT INTO %sproduct_to_layout (
-				product_id,
-				store_id,
-                layout_id)
-			VALUES (?, ?, ?)`,
-		s.prefix)
-
-	_, err := s.db.Exec(query,
-		productID, 0, 0)
-
+	err = s.updateCustomFields(ex, productId, product)
 	if err != nil {
-		return fmt.Errorf("product to layout: %v", err)
+		return err
 	}
 
 	return nil
@@ -827,56 +1143,27 @@ func (s *MySql) findProductDescription(productId, languageId int64) (*entity.Pro
 }
 
 // upsertProductDescription creates or updates a product description for the given product and language.
-// If the description exists and UpdateDescription is false, only the name is updated.
+// A new description gets all fields; an existing one only the fields listed in the request mask.
 func (s *MySql) upsertProductDescription(productId int64, productDescription *entity.ProductDescription) error {
-	var query string
-	var err error
-
 	desc, err := s.findProductDescription(productId, productDescription.LanguageId)
 	if err != nil {
 		return fmt.Errorf("lookup description: %v", err)
 	}
 
 	if desc != nil {
-		if productDescription.Description == "" || !productDescription.UpdateDescription {
-			query = fmt.Sprintf(
-				`UPDATE %sproduct_description SET
-					name = ?
-			    WHERE product_id = ? AND language_id = ?`,
-				s.prefix,
-			)
-			_, err = s.db.Exec(query,
-				productDescription.Name,
-				productId,
-				productDescription.LanguageId)
-		} else {
-			query = fmt.Sprintf(
-				`UPDATE %sproduct_description SET
-					name = ?,
-					description = ?
-			    WHERE product_id = ? AND language_id = ?`,
-				s.prefix,
-			)
-			_, err = s.db.Exec(query,
-				productDescription.Name,
-				productDescription.Description,
-				productId,
-				productDescription.LanguageId)
-		}
+		err = s.update(s.db, "product_description", productDescription.UpdateFields(),
+			"product_id = ? AND language_id = ?",
+			productId,
+			productDescription.LanguageId)
 		if err != nil {
 			return fmt.Errorf("update: %v", err)
 		}
 	} else {
+		userData := productDescription.InsertFields()
+		userData["product_id"] = productId
+		userData["language_id"] = productDescription.LanguageId
 
-		userData := map[string]interface{}{
-			"product_id":  productId,
-			"language_id": productDescription.LanguageId,
-			"name":        productDescription.Name,
-			"description": productDescription.Description,
-			"meta_title":  productDescription.Name,
-		}
-
-		_, err = s.insert("product_description", userData)
+		_, err = s.insert(s.db, "product_description", userData)
 		if err != nil {
 			return err
 		}
@@ -887,7 +1174,7 @@ func (s *MySql) upsertProductDescription(productId int64, productDescription *en
 
 // findProductSpecial checks whether a special price record exists for the given product and customer group.
 func (s *MySql) findProductSpecial(productId int64, customerGroupId int64) (bool, error) {
-	stmt, err := s.stmtFindProductSpecial()
+	stmt, err := s.stmtFindProductSpecial(s.db)
 	if err != nil {
 		return false, err
 	}
@@ -924,7 +1211,7 @@ func (s *MySql) upsertProductSpecial(productId int64, special *entity.ProductSpe
 
 	if exists {
 		// Update existing record
-		stmt, err := s.stmtUpdateProductSpecial()
+		stmt, err := s.stmtUpdateProductSpecial(s.db)
 		if err != nil {
 			return err
 		}
@@ -948,7 +1235,7 @@ func (s *MySql) upsertProductSpecial(productId int64, special *entity.ProductSpe
 			"date_end":          special.DateEnd,
 			"priority":          special.Priority,
 		}
-		_, err = s.insert("product_special", specialData)
+		_, err = s.insert(s.db, "product_special", specialData)
 		if err != nil {
 			return fmt.Errorf("insert: %v", err)
 		}
@@ -965,7 +1252,7 @@ func (s *MySql) addAttribute(attribute *entity.Attribute) (int64, error) {
 		"sort_order":         attribute.SortOrder,
 	}
 
-	attributeId, err := s.insert("attribute", userData)
+	attributeId, err := s.insert(s.db, "attribute", userData)
 	if err != nil {
 		return 0, err
 	}
@@ -976,7 +1263,7 @@ func (s *MySql) addAttribute(attribute *entity.Attribute) (int64, error) {
 // updateAttribute updates an existing attribute's UID, group ID, and sort order.
 func (s *MySql) updateAttribute(attributeId int64, attribute *entity.Attribute) error {
 
-	stmt, err := s.stmtUpdateAttribute()
+	stmt, err := s.stmtUpdateAttribute(s.db)
 	if err != nil {
 		return err
 	}
@@ -1054,7 +1341,7 @@ func (s *MySql) upsertAttributeDescription(attributeId int64, attributeDescripti
 			"name":         attributeDescription.Name,
 		}
 
-		_, err = s.insert("attribute_description", userData)
+		_, err = s.insert(s.db, "attribute_description", userData)
 		if err != nil {
 			return err
 		}
@@ -1128,7 +1415,7 @@ func (s *MySql) upsertProductAttribute(productId, attributeId, languageId int64,
 			"text":         text,
 		}
 
-		_, err = s.insert("product_attribute", userData)
+		_, err = s.insert(s.db, "product_attribute", userData)
 		if err != nil {
 			return err
 		}
@@ -1138,8 +1425,8 @@ func (s *MySql) upsertProductAttribute(productId, attributeId, languageId int64,
 }
 
 // getProductByUID returns the product_id for a given product UID, or 0 if not found.
-func (s *MySql) getProductByUID(uid string) (int64, error) {
-	stmt, err := s.stmtSelectProductId()
+func (s *MySql) getProductByUID(ex executor, uid string) (int64, error) {
+	stmt, err := s.stmtSelectProductId(ex)
 	if err != nil {
 		return 0, err
 	}
@@ -1156,13 +1443,13 @@ func (s *MySql) getProductByUID(uid string) (int64, error) {
 }
 
 // getCategoryByUID returns the category_id for a given category UID.
-// If the category does not exist, it creates a new one and returns its ID.
-func (s *MySql) getCategoryByUID(uid string) (int64, error) {
+// If the category does not exist, it creates a new one in the default stores and returns its ID.
+func (s *MySql) getCategoryByUID(ex executor, uid string) (int64, error) {
 	if uid == "" {
 		return 0, nil
 	}
 
-	stmt, err := s.stmtCategoryId()
+	stmt, err := s.stmtCategoryId(ex)
 	if err != nil {
 		return 0, err
 	}
@@ -1185,19 +1472,22 @@ func (s *MySql) getCategoryByUID(uid string) (int64, error) {
 		"date_added":    time.Now(),
 		"date_modified": time.Now(),
 	}
-	categoryId, err = s.insert("category", userData)
+	categoryId, err = s.insert(ex, "category", userData)
 	if err != nil {
 		return 0, err
 	}
+	if err = s.insertCategoryPath(ex, categoryId, []int64{categoryId}); err != nil {
+		return 0, err
+	}
 
-	_ = s.addCategoryToStore(categoryId)
+	_ = s.setCategoryStores(ex, categoryId, s.defaultStores)
 
 	return categoryId, nil
 }
 
 // getAttributeByUID returns the attribute_id for a given attribute UID, or 0 if not found.
 func (s *MySql) getAttributeByUID(uid string) (int64, error) {
-	stmt, err := s.stmtSelectAttributeId()
+	stmt, err := s.stmtSelectAttributeId(s.db)
 	if err != nil {
 		return 0, err
 	}
@@ -1214,8 +1504,8 @@ func (s *MySql) getAttributeByUID(uid string) (int64, error) {
 }
 
 // updateCategory updates an existing category's parent, sort order, status, and other fields.
-func (s *MySql) updateCategory(category *entity.Category) error {
-	stmt, err := s.stmtUpdateCategory()
+func (s *MySql) updateCategory(ex executor, category *entity.Category) error {
+	stmt, err := s.stmtUpdateCategory(ex)
 	if err != nil {
 		return err
 	}
@@ -1236,7 +1526,7 @@ func (s *MySql) updateCategory(category *entity.Category) error {
 
 // findCategoryDescription looks up a category description by category ID and language ID. Returns nil if not found.
 func (s *MySql) findCategoryDescription(categoryId, languageId int64) (*entity.CategoryDescription, error) {
-	stmt, err := s.stmtCategoryDescription()
+	stmt, err := s.stmtCategoryDescription(s.db)
 	if err != nil {
 		return nil, err
 	}
@@ -1258,10 +1548,10 @@ func (s *MySql) findCategoryDescription(categoryId, languageId int64) (*entity.C
 // If the description is empty, only the name is updated.
 func (s *MySql) upsertCategoryDescription(categoryDesc *entity.CategoryDescription) error {
 	if categoryDesc.CategoryId == 0 {
-		return fmt.Errorf("category id not provided")
+		return fmt.Errorf("category id not provided: %w", entity.ErrInvalid)
 	}
 	if categoryDesc.LanguageId == 0 {
-		return fmt.Errorf("language id not provided")
+		return fmt.Errorf("language id not provided: %w", entity.ErrInvalid)
 	}
 
 	description, err := s.findCategoryDescription(categoryDesc.CategoryId, categoryDesc.LanguageId)
@@ -1271,7 +1561,7 @@ func (s *MySql) upsertCategoryDescription(categoryDesc *entity.CategoryDescripti
 
 	if description != nil {
 		if categoryDesc.Description != "" {
-			stmt, e := s.stmtUpdateCategoryDescription()
+			stmt, e := s.stmtUpdateCategoryDescription(s.db)
 			if e != nil {
 				return e
 			}
@@ -1281,7 +1571,7 @@ func (s *MySql) upsertCategoryDescription(categoryDesc *entity.CategoryDescripti
 				categoryDesc.CategoryId,
 				categoryDesc.LanguageId)
 		} else {
-			stmt, e := s.stmtUpdateCategoryName()
+			stmt, e := s.stmtUpdateCategoryName(s.db)
 			if e != nil {
 				return e
 			}
@@ -1302,7 +1592,7 @@ func (s *MySql) upsertCategoryDescription(categoryDesc *entity.CategoryDescripti
 			"meta_title":       categoryDesc.Name,
 			"meta_description": categoryDesc.Name,
 		}
-		_, err = s.insert("category_description", userData)
+		_, err = s.insert(s.db, "category_description", userData)
 		if err != nil {
 			return err
 		}
@@ -1311,12 +1601,23 @@ func (s *MySql) upsertCategoryDescription(categoryDesc *entity.CategoryDescripti
 }
 
 // getManufacturerId returns the manufacturer_id for a given name.
-// If the manufacturer does not exist, it creates a new one with a default store association.
-func (s *MySql) getManufacturerId(name string) (int64, error) {
+// If the manufacturer does not exist, it creates a new one linked to the given stores, or to the default stores if nil.
+// In strict mode the name is a manufacturer UID, and an unknown UID is an error instead.
+func (s *MySql) getManufacturerId(ex executor, name string, stores []int64) (int64, error) {
 	if name == "" {
 		return 0, nil
 	}
-	stmt, err := s.stmtManufacturerId()
+	if s.manufacturerStrict {
+		manufacturerId, err := s.getManufacturerByUID(ex, name)
+		if err != nil {
+			return 0, err
+		}
+		if manufacturerId == 0 {
+			return 0, fmt.Errorf("manufacturer %s: %w", name, entity.ErrNotFound)
+		}
+		return manufacturerId, nil
+	}
+	stmt, err := s.stmtManufacturerId(ex)
 	if err != nil {
 		return 0, err
 	}
@@ -1333,21 +1634,19 @@ func (s *MySql) getManufacturerId(name string) (int64, error) {
 		return manufacturerId, nil
 	}
 
-	query := fmt.Sprintf(`INSERT INTO %smanufacturer (name) VALUES (?)`, s.prefix)
-	res, err := s.db.Exec(query, name)
+	manufacturerId, err = s.insert(ex, "manufacturer", map[string]interface{}{"name": name})
 	if err != nil {
 		return 0, err
 	}
 
-	manufacturerId, err = res.LastInsertId()
-	if err != nil {
-		return 0, err
+	if stores == nil {
+		stores = s.defaultStores
 	}
-
-	query = fmt.Sprintf(`INSERT INTO %smanufacturer_to_store (manufacturer_id, store_id) VALUES (?, 0)`, s.prefix)
-	_, err = s.db.Exec(query, manufacturerId)
-	if err != nil {
-		return 0, err
+	query := fmt.Sprintf(`INSERT INTO %smanufacturer_to_store (manufacturer_id, store_id) VALUES (?, ?)`, s.prefix)
+	for _, store := range stores {
+		if _, err = ex.Exec(query, manufacturerId, store); err != nil {
+			return 0, err
+		}
 	}
 
 	return manufacturerId, nil
@@ -1356,11 +1655,15 @@ func (s *MySql) getManufacturerId(name string) (int64, error) {
 // allowedReadTables defines tables that can be queried via ReadTable
 var allowedReadTables = map[string]bool{
 	"product": true, "product_description": true, "product_image": true,
-	"product_attribute": true, "product_special": true, "product_to_category": true,
+	"product_attribute": true, "product_special": true, "product_discount": true, "product_to_category": true,
 	"category": true, "category_description": true,
 	"order": true, "order_product": true, "order_total": true, "order_history": true,
 	"attribute": true, "attribute_description": true,
 	"manufacturer": true, "currency": true,
+	"seo_url": true, "url_alias": true,
+	"option": true, "option_description": true, "option_value": true, "option_value_description": true,
+	"product_option": true, "product_option_value": true,
+	"download": true, "download_description": true, "product_to_download": true,
 }
 
 // dangerousSQLPatterns contains patterns that indicate SQL injection attempts
@@ -1400,7 +1703,7 @@ func (s *MySql) ReadTable(table, filter string, limit int, plain bool) (interfac
 		return nil, fmt.Errorf("invalid filter: contains forbidden pattern")
 	}
 
-	query := fmt.Sprintf("SELECT * FROM %s%s", s.prefix, tableName)
+	query := fmt.Sprintf("SELECT * FROM `%s%s`", s.prefix, tableName)
 	if filter != "" {
 		query = fmt.Sprintf("%s WHERE %s", query, filter)
 	}
@@ -1498,7 +1801,7 @@ func (s *MySql) FinalizeProductBatch(batchUid string) (int, error) {
 
 // OrderSearchId retrieves a full order record by its ID. Returns nil if not found.
 func (s *MySql) OrderSearchId(orderId int64) (*entity.Order, error) {
-	stmt, err := s.stmtSelectOrder()
+	stmt, err := s.stmtSelectOrder(s.db)
 	if err != nil {
 		return nil, err
 	}
@@ -1576,7 +1879,7 @@ func (s *MySql) OrderSearchId(orderId int64) (*entity.Order, error) {
 
 // OrderSearchStatus returns a list of order IDs matching the given status and created after the specified time.
 func (s *MySql) OrderSearchStatus(statusId int64, from time.Time) ([]int64, error) {
-	stmt, err := s.stmtSelectOrderStatus()
+	stmt, err := s.stmtSelectOrderStatus(s.db)
 	if err != nil {
 		return nil, err
 	}
@@ -1606,7 +1909,7 @@ func (s *MySql) OrderSearchStatus(statusId int64, from time.Time) ([]int64, erro
 
 // OrderProducts returns all product line items for the given order ID.
 func (s *MySql) OrderProducts(orderId int64) ([]*entity.ProductOrder, error) {
-	stmt, err := s.stmtSelectOrderProducts()
+	stmt, err := s.stmtSelectOrderProducts(s.db)
 	if err != nil {
 		return nil, err
 	}
@@ -1657,7 +1960,7 @@ func (s *MySql) OrderProducts(orderId int64) ([]*entity.ProductOrder, error) {
 
 // OrderTotals returns all total line items (subtotal, tax, shipping, etc.) for the given order ID.
 func (s *MySql) OrderTotals(orderId int64) ([]*entity.OrderTotal, error) {
-	stmt, err := s.stmtSelectOrderTotals()
+	stmt, err := s.stmtSelectOrderTotals(s.db)
 	if err != nil {
 		return nil, err
 	}
@@ -1691,7 +1994,7 @@ func (s *MySql) OrderTotals(orderId int64) ([]*entity.OrderTotal, error) {
 
 // UpdateOrderStatus updates the current order status only if 'statusId' is less than the current status_id value
 func (s *MySql) UpdateOrderStatus(orderId int64, statusId int, comment string) error {
-	stmt, err := s.stmtUpdateOrderStatus()
+	stmt, err := s.stmtUpdateOrderStatus(s.db)
 	if err != nil {
 		return err
 	}
@@ -1715,7 +2018,7 @@ func (s *MySql) UpdateOrderStatus(orderId int64, statusId int, comment string) e
 			"comment":         comment,
 			"date_added":      time.Now(),
 		}
-		_, err = s.insert("order_history", rec)
+		_, err = s.insert(s.db, "order_history", rec)
 		if err != nil {
 			return fmt.Errorf("insert order history: %w", err)
 		}
@@ -1726,7 +2029,7 @@ func (s *MySql) UpdateOrderStatus(orderId int64, statusId int, comment string) e
 
 // UpdateCurrencyValue sets the exchange rate value for the given currency code.
 func (s *MySql) UpdateCurrencyValue(currencyCode string, value float64) error {
-	stmt, err := s.stmtUpdateCurrencyValue()
+	stmt, err := s.stmtUpdateCurrencyValue(s.db)
 	if err != nil {
 		return err
 	}
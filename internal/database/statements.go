@@ -5,13 +5,13 @@ import (
 	"fmt"
 )
 
-func (s *MySql) prepareStmt(name, query string) (*sql.Stmt, error) {
+func (s *MySql) prepareStmt(ex executor, name, query string) (*sql.Stmt, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	// если уже есть — возвращаем
 	if stmt, ok := s.statements[name]; ok {
-		return stmt, nil
+		return bindStmt(ex, stmt), nil
 	}
 
 	// подготавливаем новый
@@ -21,7 +21,7 @@ func (s *MySql) prepareStmt(name, query string) (*sql.Stmt, error) {
 	}
 
 	s.statements[name] = stmt
-	return stmt, nil
+	return bindStmt(ex, stmt), nil
 }
 
 func (s *MySql) closeStmt() {
@@ -34,22 +34,22 @@ func (s *MySql) closeStmt() {
 	}
 }
 
-func (s *MySql) stmtSelectProductId() (*sql.Stmt, error) {
+func (s *MySql) stmtSelectProductId(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(`SELECT product_id FROM %sproduct WHERE product_uid=? LIMIT 1`, s.prefix)
-	return s.prepareStmt("selectProductId", query)
+	return s.prepareStmt(ex, "selectProductId", query)
 }
 
-func (s *MySql) stmtCategoryId() (*sql.Stmt, error) {
+func (s *MySql) stmtCategoryId(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(`SELECT category_id FROM %scategory WHERE category_uid=? LIMIT 1`, s.prefix)
-	return s.prepareStmt("selectCategoryId", query)
+	return s.prepareStmt(ex, "selectCategoryId", query)
 }
 
-func (s *MySql) stmtSelectAttributeId() (*sql.Stmt, error) {
+func (s *MySql) stmtSelectAttributeId(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(`SELECT attribute_id FROM %sattribute WHERE attribute_uid=? LIMIT 1`, s.prefix)
-	return s.prepareStmt("selectAttributeId", query)
+	return s.prepareStmt(ex, "selectAttributeId", query)
 }
 
-func (s *MySql) stmtCategoryDescription() (*sql.Stmt, error) {
+func (s *MySql) stmtCategoryDescription(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`SELECT
 					name,
@@ -59,15 +59,15 @@ func (s *MySql) stmtCategoryDescription() (*sql.Stmt, error) {
 				LIMIT 1`,
 		s.prefix,
 	)
-	return s.prepareStmt("selectCategoryDescription", query)
+	return s.prepareStmt(ex, "selectCategoryDescription", query)
 }
 
-func (s *MySql) stmtManufacturerId() (*sql.Stmt, error) {
+func (s *MySql) stmtManufacturerId(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(`SELECT manufacturer_id FROM %smanufacturer WHERE name=? LIMIT 1`, s.prefix)
-	return s.prepareStmt("selectManufacturerId", query)
+	return s.prepareStmt(ex, "selectManufacturerId", query)
 }
 
-func (s *MySql) stmtSelectOrder() (*sql.Stmt, error) {
+func (s *MySql) stmtSelectOrder(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`SELECT
             order_id,
@@ -135,10 +135,10 @@ func (s *MySql) stmtSelectOrder() (*sql.Stmt, error) {
          LIMIT 1`,
 		s.prefix,
 	)
-	return s.prepareStmt("selectOrder", query)
+	return s.prepareStmt(ex, "selectOrder", query)
 }
 
-func (s *MySql) stmtSelectOrderStatus() (*sql.Stmt, error) {
+func (s *MySql) stmtSelectOrderStatus(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`SELECT
 			order_id
@@ -147,10 +147,10 @@ func (s *MySql) stmtSelectOrderStatus() (*sql.Stmt, error) {
 		 LIMIT 100`,
 		s.prefix,
 	)
-	return s.prepareStmt("selectOrderStatus", query)
+	return s.prepareStmt(ex, "selectOrderStatus", query)
 }
 
-func (s *MySql) stmtSelectOrderProducts() (*sql.Stmt, error) {
+func (s *MySql) stmtSelectOrderProducts(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`SELECT
 			op.discount_amount,
@@ -178,10 +178,10 @@ func (s *MySql) stmtSelectOrderProducts() (*sql.Stmt, error) {
 		 WHERE op.order_id = ?`,
 		s.prefix, s.prefix,
 	)
-	return s.prepareStmt("selectOrderProducts", query)
+	return s.prepareStmt(ex, "selectOrderProducts", query)
 }
 
-func (s *MySql) stmtSelectOrderTotals() (*sql.Stmt, error) {
+func (s *MySql) stmtSelectOrderTotals(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`SELECT
 			op.code,
@@ -191,10 +191,10 @@ func (s *MySql) stmtSelectOrderTotals() (*sql.Stmt, error) {
 		 WHERE op.order_id = ?`,
 		s.prefix,
 	)
-	return s.prepareStmt("selectOrderTotals", query)
+	return s.prepareStmt(ex, "selectOrderTotals", query)
 }
 
-func (s *MySql) stmtUpdateCategoryDescription() (*sql.Stmt, error) {
+func (s *MySql) stmtUpdateCategoryDescription(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`UPDATE %scategory_description
 				SET
@@ -203,10 +203,10 @@ func (s *MySql) stmtUpdateCategoryDescription() (*sql.Stmt, error) {
 				WHERE category_id=? AND language_id=?`,
 		s.prefix,
 	)
-	return s.prepareStmt("updateCategoryDescription", query)
+	return s.prepareStmt(ex, "updateCategoryDescription", query)
 }
 
-func (s *MySql) stmtUpdateCategoryName() (*sql.Stmt, error) {
+func (s *MySql) stmtUpdateCategoryName(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`UPDATE %scategory_description
 				SET
@@ -214,10 +214,10 @@ func (s *MySql) stmtUpdateCategoryName() (*sql.Stmt, error) {
 				WHERE category_id=? AND language_id=?`,
 		s.prefix,
 	)
-	return s.prepareStmt("updateCategoryName", query)
+	return s.prepareStmt(ex, "updateCategoryName", query)
 }
 
-func (s *MySql) stmtUpdateCategory() (*sql.Stmt, error) {
+func (s *MySql) stmtUpdateCategory(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`UPDATE %scategory
 				SET
@@ -230,10 +230,10 @@ func (s *MySql) stmtUpdateCategory() (*sql.Stmt, error) {
 				WHERE category_id=?`,
 		s.prefix,
 	)
-	return s.prepareStmt("updateCategory", query)
+	return s.prepareStmt(ex, "updateCategory", query)
 }
 
-func (s *MySql) stmtUpdateProduct() (*sql.Stmt, error) {
+func (s *MySql) stmtUpdateProduct(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`UPDATE %sproduct SET
 				model = ?, 
@@ -249,20 +249,20 @@ func (s *MySql) stmtUpdateProduct() (*sql.Stmt, error) {
 			    WHERE product_id = ?`,
 		s.prefix,
 	)
-	return s.prepareStmt("updateProduct", query)
+	return s.prepareStmt(ex, "updateProduct", query)
 }
 
-func (s *MySql) stmtUpdateProductImage() (*sql.Stmt, error) {
-	query := fmt.Sprintf(`UPDATE %sproduct SET image = ? WHERE product_uid = ?`, s.prefix)
-	return s.prepareStmt("updateProductImage", query)
+func (s *MySql) stmtUpdateProductImage(ex executor) (*sql.Stmt, error) {
+	query := fmt.Sprintf(`UPDATE %sproduct SET image = ?, image_hash = ?, image_uid = ?, image_version = ? WHERE product_uid = ?`, s.prefix)
+	return s.prepareStmt(ex, "updateProductImage", query)
 }
 
-func (s *MySql) stmtUpdateProductImageAdd() (*sql.Stmt, error) {
-	query := fmt.Sprintf(`UPDATE %sproduct_image SET sort_order = ? WHERE product_image_id = ?`, s.prefix)
-	return s.prepareStmt("updateProductImageAdd", query)
+func (s *MySql) stmtUpdateProductImageAdd(ex executor) (*sql.Stmt, error) {
+	query := fmt.Sprintf(`UPDATE %sproduct_image SET image = ?, file_hash = ?, file_version = ?, sort_order = ? WHERE product_image_id = ?`, s.prefix)
+	return s.prepareStmt(ex, "updateProductImageAdd", query)
 }
 
-func (s *MySql) stmtUpdateAttribute() (*sql.Stmt, error) {
+func (s *MySql) stmtUpdateAttribute(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`UPDATE %sattribute SET
 				attribute_uid = ?, 
@@ -271,48 +271,48 @@ func (s *MySql) stmtUpdateAttribute() (*sql.Stmt, error) {
 			    WHERE attribute_id = ?`,
 		s.prefix,
 	)
-	return s.prepareStmt("updateAttribute", query)
+	return s.prepareStmt(ex, "updateAttribute", query)
 }
 
-func (s *MySql) stmtGetProductNotMainImage() (*sql.Stmt, error) {
+func (s *MySql) stmtGetProductNotMainImage(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		"SELECT product_image_id FROM %sproduct_image WHERE product_id=? AND file_uid=? LIMIT 1",
 		s.prefix,
 	)
-	return s.prepareStmt("getProductNotMainImage", query)
+	return s.prepareStmt(ex, "getProductNotMainImage", query)
 }
 
-func (s *MySql) stmtGetProductMainImage() (*sql.Stmt, error) {
-	query := fmt.Sprintf(`SELECT image FROM %sproduct WHERE product_uid = ? LIMIT 1`, s.prefix)
-	return s.prepareStmt("getProductMainImage", query)
+func (s *MySql) stmtGetProductMainImage(ex executor) (*sql.Stmt, error) {
+	query := fmt.Sprintf(`SELECT image, image_uid FROM %sproduct WHERE product_uid = ? LIMIT 1`, s.prefix)
+	return s.prepareStmt(ex, "getProductMainImage", query)
 }
 
-func (s *MySql) stmtGetProductImages() (*sql.Stmt, error) {
+func (s *MySql) stmtGetProductImages(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`SELECT product_image_id, file_uid
          FROM %sproduct_image
          WHERE product_id = ?`,
 		s.prefix,
 	)
-	return s.prepareStmt("getProductImages", query)
+	return s.prepareStmt(ex, "getProductImages", query)
 }
 
-func (s *MySql) stmtGetApiUsername() (*sql.Stmt, error) {
+func (s *MySql) stmtGetApiUsername(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf("SELECT username FROM %sapi WHERE `key`=? AND status=1 LIMIT 1",
 		s.prefix,
 	)
-	return s.prepareStmt("getApiUsername", query)
+	return s.prepareStmt(ex, "getApiUsername", query)
 }
 
-func (s *MySql) stmtFindProductSpecial() (*sql.Stmt, error) {
+func (s *MySql) stmtFindProductSpecial(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`SELECT product_special_id FROM %sproduct_special WHERE product_id=? AND customer_group_id=? LIMIT 1`,
 		s.prefix,
 	)
-	return s.prepareStmt("findProductSpecial", query)
+	return s.prepareStmt(ex, "findProductSpecial", query)
 }
 
-func (s *MySql) stmtUpdateProductSpecial() (*sql.Stmt, error) {
+func (s *MySql) stmtUpdateProductSpecial(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`UPDATE %sproduct_special SET
 				price = ?, 
@@ -322,10 +322,10 @@ func (s *MySql) stmtUpdateProductSpecial() (*sql.Stmt, error) {
 			    WHERE product_id = ? AND customer_group_id = ?`,
 		s.prefix,
 	)
-	return s.prepareStmt("updateProductSpecial", query)
+	return s.prepareStmt(ex, "updateProductSpecial", query)
 }
 
-func (s *MySql) stmtUpdateOrderStatus() (*sql.Stmt, error) {
+func (s *MySql) stmtUpdateOrderStatus(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`UPDATE %sorder SET
 				order_status_id = ?,
@@ -333,10 +333,10 @@ func (s *MySql) stmtUpdateOrderStatus() (*sql.Stmt, error) {
 			    WHERE order_id = ?`,
 		s.prefix,
 	)
-	return s.prepareStmt("updateOrderStatus", query)
+	return s.prepareStmt(ex, "updateOrderStatus", query)
 }
 
-func (s *MySql) stmtUpdateCurrencyValue() (*sql.Stmt, error) {
+func (s *MySql) stmtUpdateCurrencyValue(ex executor) (*sql.Stmt, error) {
 	query := fmt.Sprintf(
 		`UPDATE %scurrency SET
 				value = ?,
@@ -344,5 +344,15 @@ func (s *MySql) stmtUpdateCurrencyValue() (*sql.Stmt, error) {
 			    WHERE code = ?`,
 		s.prefix,
 	)
-	return s.prepareStmt("updateCurrencyValue", query)
+	return s.prepareStmt(ex, "updateCurrencyValue", query)
+}
+
+func (s *MySql) stmtSelectOptionId(ex executor) (*sql.Stmt, error) {
+	query := fmt.Sprintf("SELECT option_id FROM `%soption` WHERE option_uid=? LIMIT 1", s.prefix)
+	return s.prepareStmt(ex, "selectOptionId", query)
+}
+
+func (s *MySql) stmtSelectOptionValueId(ex executor) (*sql.Stmt, error) {
+	query := fmt.Sprintf(`SELECT option_value_id FROM %soption_value WHERE option_id=? AND option_value_uid=? LIMIT 1`, s.prefix)
+	return s.prepareStmt(ex, "selectOptionValueId", query)
 }
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"ocapi/entity"
+	"strings"
+	"time"
+)
+
+// stockBatchSize limits the number of products updated by a single statement.
+const stockBatchSize = 500
+
+// UpdateStock applies quantity and price changes to products by UID using multi-row CASE
+// updates, one statement per batch, and sets stock_status_id to the configured default status
+// matching the new quantity.
+// All batches are written in one transaction. Duplicate UIDs are applied once, the last item wins.
+func (s *MySql) UpdateStock(items []*entity.StockItem) (*entity.StockResult, error) {
+	latest := make(map[string]*entity.StockItem, len(items))
+	uids := make([]string, 0, len(items))
+	for _, item := range items {
+		if _, ok := latest[item.ProductUid]; !ok {
+			uids = append(uids, item.ProductUid)
+		}
+		latest[item.ProductUid] = item
+	}
+
+	result := &entity.StockResult{}
+	err := s.withTx(func(tx *sql.Tx) error {
+		for start := 0; start < len(uids); start += stockBatchSize {
+			end := min(start+stockBatchSize, len(uids))
+			batch := make([]*entity.StockItem, 0, end-start)
+			for _, uid := range uids[start:end] {
+				batch = append(batch, latest[uid])
+			}
+
+			known, err := s.knownProductUids(tx, uids[start:end])
+			if err != nil {
+				return fmt.Errorf("product search: %v", err)
+			}
+			for _, uid := range uids[start:end] {
+				if !known[uid] {
+					result.UnknownUids = append(result.UnknownUids, uid)
+				}
+			}
+
+			if err = s.updateStockBatch(tx, batch); err != nil {
+				return fmt.Errorf("stock update: %v", err)
+			}
+			result.Updated += len(known)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Unknown = len(result.UnknownUids)
+	return result, nil
+}
+
+// knownProductUids returns the set of the given UIDs that exist in the product table.
+func (s *MySql) knownProductUids(ex executor, uids []string) (map[string]bool, error) {
+	placeholders := make([]string, len(uids))
+	args := make([]interface{}, len(uids))
+	for i, uid := range uids {
+		placeholders[i] = "?"
+		args[i] = uid
+	}
+
+	query := fmt.Sprintf(`SELECT product_uid FROM %sproduct WHERE product_uid IN (%s)`, s.prefix, strings.Join(placeholders, ","))
+	rows, err := ex.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	known := make(map[string]bool, len(uids))
+	for rows.Next() {
+		var uid string
+		if err = rows.Scan(&uid); err != nil {
+			return nil, fmt.Errorf("scan: %v", err)
+		}
+		known[uid] = true
+	}
+	return known, rows.Err()
+}
+
+// updateStockBatch updates quantity, price and stock status of a batch of products in one statement.
+// MySQL applies assignments left to right, so stock_status_id sees the new quantity.
+func (s *MySql) updateStockBatch(ex executor, batch []*entity.StockItem) error {
+	var quantityCase, priceCase strings.Builder
+	quantityArgs := make([]interface{}, 0, 2*len(batch))
+	priceArgs := make([]interface{}, 0, 2*len(batch))
+	placeholders := make([]string, len(batch))
+	uidArgs := make([]interface{}, len(batch))
+
+	for i, item := range batch {
+		quantityCase.WriteString(" WHEN ? THEN ?")
+		quantityArgs = append(quantityArgs, item.ProductUid, item.Quantity)
+		if item.Price != nil {
+			priceCase.WriteString(" WHEN ? THEN ?")
+			priceArgs = append(priceArgs, item.ProductUid, *item.Price)
+		}
+		placeholders[i] = "?"
+		uidArgs[i] = item.ProductUid
+	}
+
+	price := "price"
+	if len(priceArgs) > 0 {
+		price = fmt.Sprintf("CASE product_uid%s ELSE price END", priceCase.String())
+	}
+
+	query := fmt.Sprintf(`UPDATE %sproduct SET
+				quantity = CASE product_uid%s ELSE quantity END,
+				price = %s,
+				stock_status_id = IF(quantity > 0, ?, ?),
+				date_modified = ?
+			WHERE product_uid IN (%s)`,
+		s.prefix, quantityCase.String(), price, strings.Join(placeholders, ","))
+
+	args := make([]interface{}, 0, len(quantityArgs)+len(priceArgs)+3+len(uidArgs))
+	args = append(args, quantityArgs...)
+	args = append(args, priceArgs...)
+	args = append(args, s.defaults.stockStatusIn, s.defaults.stockStatusOut, time.Now())
+	args = append(args, uidArgs...)
+
+	_, err := ex.Exec(query, args...)
+	return err
+}
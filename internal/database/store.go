@@ -0,0 +1,90 @@
+package database
+
+import (
+	"fmt"
+	"ocapi/entity"
+	"strings"
+)
+
+// resolveStores converts store IDs and configured store codes of a request into a set of store IDs.
+// Returns nil when the request specifies no stores.
+func (s *MySql) resolveStores(ids []int64, codes []string) ([]int64, error) {
+	if len(ids) == 0 && len(codes) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[int64]bool, len(ids)+len(codes))
+	stores := make([]int64, 0, len(ids)+len(codes))
+	add := func(id int64) {
+		if !seen[id] {
+			seen[id] = true
+			stores = append(stores, id)
+		}
+	}
+
+	for _, id := range ids {
+		add(id)
+	}
+	for _, code := range codes {
+		id, ok := s.storeCodes[code]
+		if !ok {
+			return nil, fmt.Errorf("store code %s: %w", code, entity.ErrInvalid)
+		}
+		add(id)
+	}
+	return stores, nil
+}
+
+// setProductStores publishes a product to exactly the given stores, with the default layout for new stores.
+func (s *MySql) setProductStores(ex executor, productId int64, stores []int64) error {
+	if err := s.syncStoreLinks(ex, "product_to_store", "product_id", productId, stores, false); err != nil {
+		return fmt.Errorf("product to store: %v", err)
+	}
+	if err := s.syncStoreLinks(ex, "product_to_layout", "product_id", productId, stores, true); err != nil {
+		return fmt.Errorf("product to layout: %v", err)
+	}
+	return nil
+}
+
+// setCategoryStores publishes a category to exactly the given stores, with the default layout for new stores.
+func (s *MySql) setCategoryStores(ex executor, categoryId int64, stores []int64) error {
+	if err := s.syncStoreLinks(ex, "category_to_store", "category_id", categoryId, stores, false); err != nil {
+		return fmt.Errorf("category to store: %v", err)
+	}
+	if err := s.syncStoreLinks(ex, "category_to_layout", "category_id", categoryId, stores, true); err != nil {
+		return fmt.Errorf("category to layout: %v", err)
+	}
+	return nil
+}
+
+// syncStoreLinks makes the store links of an entity in the table equal to stores: links to other
+// stores are removed and missing ones inserted, existing links keep their values (e.g. layout_id).
+func (s *MySql) syncStoreLinks(ex executor, table, idColumn string, id int64, stores []int64, layout bool) error {
+	placeholders := make([]string, len(stores))
+	args := make([]interface{}, 0, 1+len(stores))
+	args = append(args, id)
+	for i, store := range stores {
+		placeholders[i] = "?"
+		args = append(args, store)
+	}
+
+	query := fmt.Sprintf(`DELETE FROM %s%s WHERE %s=?`, s.prefix, table, idColumn)
+	if len(stores) > 0 {
+		query = fmt.Sprintf(`%s AND store_id NOT IN (%s)`, query, strings.Join(placeholders, ","))
+	}
+	if _, err := ex.Exec(query, args...); err != nil {
+		return fmt.Errorf("delete: %v", err)
+	}
+
+	if layout {
+		query = fmt.Sprintf(`INSERT IGNORE INTO %s%s (%s, store_id, layout_id) VALUES (?, ?, 0)`, s.prefix, table, idColumn)
+	} else {
+		query = fmt.Sprintf(`INSERT IGNORE INTO %s%s (%s, store_id) VALUES (?, ?)`, s.prefix, table, idColumn)
+	}
+	for _, store := range stores {
+		if _, err := ex.Exec(query, id, store); err != nil {
+			return fmt.Errorf("insert: %v", err)
+		}
+	}
+	return nil
+}
@@ -76,7 +76,7 @@ func (s *MySql) addColumnIfNotExists(tableName, columnName, columnType string) e
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			// Column does not exist, so add it
-			alterQuery := fmt.Sprintf(`ALTER TABLE %s%s ADD COLUMN %s %s`, s.prefix, tableName, columnName, columnType)
+			alterQuery := fmt.Sprintf("ALTER TABLE `%s%s` ADD COLUMN %s %s", s.prefix, tableName, columnName, columnType)
 			_, err = s.db.Exec(alterQuery)
 			if err != nil {
 				return fmt.Errorf("add column %s to table %s: %w", columnName, tableName, err)
@@ -105,7 +105,7 @@ func (s *MySql) readStructure(table string) (map[string]Column, error) {
 	return tableInfo, nil
 }
 
-func (s *MySql) insert(table string, userData map[string]interface{}) (int64, error) {
+func (s *MySql) insert(ex executor, table string, userData map[string]interface{}) (int64, error) {
 
 	// Получаем структуру таблицы
 	tableInfo, err := s.readStructure(table)
@@ -165,7 +165,7 @@ func (s *MySql) insert(table string, userData map[string]interface{}) (int64, er
 		strings.Join(colNames, ", "),
 		strings.Join(placeholders, ", "),
 	)
-	res, err := s.db.Exec(insertSQL, values...)
+	res, err := ex.Exec(insertSQL, values...)
 	if err != nil {
 		return 0, fmt.Errorf("%s insert: %w", table, err)
 	}
@@ -179,7 +179,7 @@ func (s *MySql) insert(table string, userData map[string]interface{}) (int64, er
 	return rowId, nil
 }
 
-func (s *MySql) update(table string, userData map[string]interface{}, whereClause string, whereArgs ...interface{}) error {
+func (s *MySql) update(ex executor, table string, userData map[string]interface{}, whereClause string, whereArgs ...interface{}) error {
 	// Получаем структуру таблицы
 	tableInfo, err := s.readStructure(table)
 	if err != nil {
@@ -217,7 +217,7 @@ func (s *MySql) update(table string, userData map[string]interface{}, whereClaus
 	// Объединяем значения для SET и WHERE
 	values = append(values, whereArgs...)
 
-	_, err = s.db.Exec(updateSQL, values...)
+	_, err = ex.Exec(updateSQL, values...)
 	if err != nil {
 		return fmt.Errorf("%s update: %w", table, err)
 	}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// executor is the common subset of *sql.DB and *sql.Tx used by the query helpers,
+// so the same code path can run either directly on the pool or inside a transaction.
+type executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// bindStmt returns the cached prepared statement bound to the executor.
+// Inside a transaction the statement is re-bound with tx.Stmt and is closed
+// automatically on commit or rollback.
+func bindStmt(ex executor, stmt *sql.Stmt) *sql.Stmt {
+	if tx, ok := ex.(*sql.Tx); ok {
+		return tx.Stmt(stmt)
+	}
+	return stmt
+}
+
+// withTx runs fn inside a new transaction, commits on success and rolls back on any error.
+func (s *MySql) withTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err = fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w; rollback: %v", err, rbErr)
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
@@ -14,11 +14,18 @@ import (
 	"ocapi/internal/http-server/handlers/batch"
 	"ocapi/internal/http-server/handlers/category"
 	"ocapi/internal/http-server/handlers/currency"
+	"ocapi/internal/http-server/handlers/dictionary"
+	"ocapi/internal/http-server/handlers/download"
 	"ocapi/internal/http-server/handlers/errors"
 	"ocapi/internal/http-server/handlers/fetch"
+	"ocapi/internal/http-server/handlers/images"
+	"ocapi/internal/http-server/handlers/language"
+	"ocapi/internal/http-server/handlers/manufacturer"
+	"ocapi/internal/http-server/handlers/option"
 	"ocapi/internal/http-server/handlers/order"
 	"ocapi/internal/http-server/handlers/product"
 	"ocapi/internal/http-server/handlers/service"
+	"ocapi/internal/http-server/handlers/stock"
 	"ocapi/internal/http-server/middleware/authenticate"
 	"ocapi/internal/http-server/middleware/timeout"
 	"ocapi/internal/lib/sl"
@@ -35,9 +42,16 @@ type Handler interface {
 	service.Service
 	product.Core
 	attribute.Core
+	option.Core
+	download.Core
+	manufacturer.Core
 	category.Core
 	order.Core
 	currency.Core
+	stock.Core
+	images.Core
+	language.Core
+	dictionary.Core
 	fetch.Core
 	batch.Core
 }
@@ -72,18 +86,40 @@ func New(conf *config.Config, log *slog.Logger, handler Handler) (*Server, error
 			v1.Route("/product", func(r chi.Router) {
 				r.Get("/{uid}", product.UidSearch(log, handler))
 				r.Post("/", product.SaveProduct(log, handler))
+				r.Delete("/", product.DeleteProducts(log, handler))
+				r.Delete("/{uid}", product.Delete(log, handler))
 				r.Post("/description", product.SaveDescription(log, handler))
 				r.Post("/attribute", product.SaveAttribute(log, handler))
 				r.Post("/image", product.SaveImage(log, handler))
+				r.Post("/image/upload", product.UploadImage(log, handler))
+				r.Post("/image/check", product.CheckImages(log, handler))
 				r.Post("/images", product.SetImages(log, handler))
 				r.Post("/special", product.SaveSpecial(log, handler))
+				r.Post("/special/purge", product.PurgeSpecials(log, handler))
+				r.Post("/discount", product.SaveDiscount(log, handler))
+				r.Post("/option", product.SaveOption(log, handler))
+				r.Post("/download", product.SetDownloads(log, handler))
+			})
+			v1.Route("/products", func(r chi.Router) {
+				r.Get("/", product.Changes(log, handler))
 			})
 			v1.Route("/attribute", func(r chi.Router) {
 				r.Post("/", attribute.Save(log, handler))
 			})
+			v1.Route("/option", func(r chi.Router) {
+				r.Post("/", option.Save(log, handler))
+			})
+			v1.Route("/manufacturer", func(r chi.Router) {
+				r.Post("/", manufacturer.Save(log, handler))
+				r.Post("/merge", manufacturer.Merge(log, handler))
+			})
+			v1.Route("/download", func(r chi.Router) {
+				r.Post("/", download.Save(log, handler))
+			})
 			v1.Route("/category", func(r chi.Router) {
 				r.Post("/", category.SaveCategory(log, handler))
 				r.Post("/description", category.SaveDescription(log, handler))
+				r.Post("/repair", category.Repair(log, handler))
 			})
 			v1.Route("/order", func(r chi.Router) {
 				r.Get("/{orderId}", order.SearchId(log, handler))
@@ -102,6 +138,18 @@ func New(conf *config.Config, log *slog.Logger, handler Handler) (*Server, error
 			v1.Route("/currency", func(r chi.Router) {
 				r.Post("/", currency.Update(log, handler))
 			})
+			v1.Route("/stock", func(r chi.Router) {
+				r.Post("/", stock.Update(log, handler))
+			})
+			v1.Route("/images", func(r chi.Router) {
+				r.Post("/cleanup", images.Cleanup(log, handler))
+				r.Get("/quarantine", images.Quarantine(log, handler))
+			})
+			v1.Get("/languages", language.List(log, handler))
+			v1.Route("/dictionaries", func(r chi.Router) {
+				r.Post("/refresh", dictionary.Refresh(log, handler))
+				r.Get("/{name}", dictionary.Get(log, handler))
+			})
 		})
 	})
 
@@ -12,7 +12,7 @@ import (
 )
 
 type Core interface {
-	LoadAttributes(attributes []*entity.Attribute) error
+	LoadAttributes(attributes []*entity.Attribute, report bool) ([]*entity.ItemResult, error)
 }
 
 func Save(log *slog.Logger, handler Core) http.HandlerFunc {
@@ -44,7 +44,8 @@ func Save(log *slog.Logger, handler Core) http.HandlerFunc {
 			slog.Int("size", len(body.Data)),
 		)
 
-		err := handler.LoadAttributes(body.Data)
+		report := r.URL.Query().Get("report") == "items"
+		results, err := handler.LoadAttributes(body.Data, report)
 		if err != nil {
 			logger.Error("load attributes", sl.Err(err))
 			render.JSON(w, r, response.Error(fmt.Sprintf("Save data failed: %v", err)))
@@ -52,6 +53,10 @@ func Save(log *slog.Logger, handler Core) http.HandlerFunc {
 		}
 		logger.Debug("attributes data saved")
 
+		if report {
+			render.JSON(w, r, response.Items(results))
+			return
+		}
 		render.JSON(w, r, response.Ok(nil))
 	}
 }
@@ -3,6 +3,7 @@ package category
 import "ocapi/entity"
 
 type Core interface {
-	LoadCategories(categories []*entity.CategoryData) error
-	LoadCategoryDescriptions(categories []*entity.CategoryDescriptionData) error
+	LoadCategories(categories []*entity.CategoryData, report bool) ([]*entity.ItemResult, error)
+	LoadCategoryDescriptions(categories []*entity.CategoryDescriptionData, report bool) ([]*entity.ItemResult, error)
+	RepairCategories() (*entity.CategoryRepairResult, error)
 }
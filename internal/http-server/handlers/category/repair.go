@@ -0,0 +1,38 @@
+package category
+
+import (
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"ocapi/internal/lib/api/response"
+	"ocapi/internal/lib/sl"
+)
+
+func Repair(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.category")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("category service not available")
+			render.JSON(w, r, response.Error("Category service not available"))
+			return
+		}
+
+		result, err := handler.RepairCategories()
+		if err != nil {
+			logger.Error("repair categories", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Repair failed: %v", err)))
+			return
+		}
+		logger.With(slog.Int("categories", result.Categories)).Debug("category paths repaired")
+
+		render.JSON(w, r, response.Ok(result))
+	}
+}
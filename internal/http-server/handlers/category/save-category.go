@@ -35,7 +35,8 @@ func SaveCategory(log *slog.Logger, handler Core) http.HandlerFunc {
 		}
 		logger = logger.With(slog.Int("size", len(body.Data)))
 
-		err := handler.LoadCategories(body.Data)
+		report := r.URL.Query().Get("report") == "items"
+		results, err := handler.LoadCategories(body.Data, report)
 		if err != nil {
 			logger.Error("load categories", sl.Err(err))
 			render.Status(r, 400)
@@ -44,6 +45,10 @@ func SaveCategory(log *slog.Logger, handler Core) http.HandlerFunc {
 		}
 		logger.Debug("category data saved")
 
+		if report {
+			render.JSON(w, r, response.Items(results))
+			return
+		}
 		render.JSON(w, r, response.Ok(nil))
 	}
 }
@@ -35,7 +35,8 @@ func SaveDescription(log *slog.Logger, handler Core) http.HandlerFunc {
 		}
 		logger = logger.With(slog.Int("size", len(body.Data)))
 
-		err := handler.LoadCategoryDescriptions(body.Data)
+		report := r.URL.Query().Get("report") == "items"
+		results, err := handler.LoadCategoryDescriptions(body.Data, report)
 		if err != nil {
 			logger.Error("load descriptions", sl.Err(err))
 			render.JSON(w, r, response.Error(fmt.Sprintf("Save data: %v", err)))
@@ -43,6 +44,10 @@ func SaveDescription(log *slog.Logger, handler Core) http.HandlerFunc {
 		}
 		logger.Debug("category saved")
 
+		if report {
+			render.JSON(w, r, response.Items(results))
+			return
+		}
 		render.JSON(w, r, response.Ok(nil))
 	}
 }
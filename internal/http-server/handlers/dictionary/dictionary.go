@@ -0,0 +1,78 @@
+package dictionary
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"ocapi/entity"
+	"ocapi/internal/lib/api/response"
+	"ocapi/internal/lib/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Core interface {
+	Dictionary(name string) (*entity.Dictionary, error)
+	RefreshDictionaries() error
+}
+
+func Get(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.dictionary")
+		name := chi.URLParam(r, "name")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			slog.String("dictionary", name),
+		)
+
+		if handler == nil {
+			logger.Error("dictionary service not available")
+			render.JSON(w, r, response.Error("Dictionary service not available"))
+			return
+		}
+
+		dictionary, err := handler.Dictionary(name)
+		if err != nil {
+			logger.Error("dictionary", sl.Err(err))
+			if errors.Is(err, entity.ErrNotFound) {
+				render.Status(r, 404)
+			}
+			render.JSON(w, r, response.Error(fmt.Sprintf("Dictionary failed: %v", err)))
+			return
+		}
+		logger.With(slog.Int("size", dictionary.Size)).Debug("dictionary")
+
+		render.JSON(w, r, response.Ok(dictionary))
+	}
+}
+
+func Refresh(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.dictionary")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("dictionary service not available")
+			render.JSON(w, r, response.Error("Dictionary service not available"))
+			return
+		}
+
+		if err := handler.RefreshDictionaries(); err != nil {
+			logger.Error("refresh dictionaries", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Refresh failed: %v", err)))
+			return
+		}
+		logger.Debug("dictionaries refreshed")
+
+		render.JSON(w, r, response.Ok(nil))
+	}
+}
@@ -0,0 +1,52 @@
+package download
+
+import (
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"ocapi/entity"
+	"ocapi/internal/lib/api/response"
+	"ocapi/internal/lib/sl"
+)
+
+type Core interface {
+	LoadDownloads(downloads []*entity.Download) error
+}
+
+func Save(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.download")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("download service not available")
+			render.JSON(w, r, response.Error("Download service not available"))
+			return
+		}
+
+		var body entity.DownloadRequest
+		if err := render.Bind(r, &body); err != nil {
+			logger.Error("bind request data", sl.Err(err))
+			render.Status(r, 400)
+			render.JSON(w, r, response.Error(fmt.Sprintf("Failed to decode: %v", err)))
+			return
+		}
+		logger = logger.With(slog.Int("size", len(body.Data)))
+
+		err := handler.LoadDownloads(body.Data)
+		if err != nil {
+			logger.Error("load downloads", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Save download: %v", err)))
+			return
+		}
+		logger.Debug("downloads saved")
+
+		render.JSON(w, r, response.Ok(nil))
+	}
+}
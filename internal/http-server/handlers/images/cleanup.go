@@ -0,0 +1,75 @@
+package images
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"ocapi/internal/lib/api/response"
+	"ocapi/internal/lib/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// Cleanup moves image files not used by any product to quarantine; ?dry_run=true only lists them
+func Cleanup(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.images")
+		dryRun := r.URL.Query().Get("dry_run") == "true"
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			slog.Bool("dry_run", dryRun),
+		)
+
+		if handler == nil {
+			logger.Error("image service not available")
+			render.JSON(w, r, response.Error("Image service not available"))
+			return
+		}
+
+		report, err := handler.CleanupImages(dryRun)
+		if err != nil {
+			logger.Error("image cleanup", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Image cleanup failed: %v", err)))
+			return
+		}
+		logger.With(
+			slog.Int("orphans", len(report.Orphans)),
+			slog.Int("purged", len(report.Purged)),
+			slog.Int("quarantined", report.Quarantined),
+			slog.Int("skipped", report.Skipped),
+		).Info("image cleanup")
+
+		render.JSON(w, r, response.Ok(report))
+	}
+}
+
+// Quarantine lists the image files removed by the cleanup and kept in quarantine
+func Quarantine(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.images")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("image service not available")
+			render.JSON(w, r, response.Error("Image service not available"))
+			return
+		}
+
+		files, err := handler.QuarantinedImages()
+		if err != nil {
+			logger.Error("quarantined images", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Quarantine listing failed: %v", err)))
+			return
+		}
+		logger.With(slog.Int("size", len(files))).Debug("quarantined images")
+
+		render.JSON(w, r, response.Ok(files))
+	}
+}
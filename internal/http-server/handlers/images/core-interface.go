@@ -0,0 +1,8 @@
+package images
+
+import "ocapi/entity"
+
+type Core interface {
+	CleanupImages(dryRun bool) (*entity.ImageCleanupReport, error)
+	QuarantinedImages() ([]*entity.QuarantinedImage, error)
+}
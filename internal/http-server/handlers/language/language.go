@@ -0,0 +1,44 @@
+package language
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"ocapi/entity"
+	"ocapi/internal/lib/api/response"
+	"ocapi/internal/lib/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Core interface {
+	Languages() ([]*entity.Language, error)
+}
+
+func List(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.language")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("language service not available")
+			render.JSON(w, r, response.Error("Language service not available"))
+			return
+		}
+
+		languages, err := handler.Languages()
+		if err != nil {
+			logger.Error("languages", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Languages failed: %v", err)))
+			return
+		}
+		logger.With(slog.Int("size", len(languages))).Debug("languages")
+
+		render.JSON(w, r, response.Ok(languages))
+	}
+}
@@ -0,0 +1,100 @@
+package manufacturer
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"ocapi/entity"
+	"ocapi/internal/lib/api/response"
+	"ocapi/internal/lib/sl"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Core interface {
+	LoadManufacturers(manufacturers []*entity.Manufacturer, report bool) ([]*entity.ItemResult, error)
+	MergeManufacturers(merge *entity.ManufacturerMerge) (*entity.ManufacturerMergeResult, error)
+}
+
+func Save(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.manufacturer")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("manufacturer service not available")
+			render.JSON(w, r, response.Error("Manufacturer service not available"))
+			return
+		}
+
+		var body entity.ManufacturerRequest
+		if err := render.Bind(r, &body); err != nil {
+			logger.Error("bind request data", sl.Err(err))
+			render.Status(r, 400)
+			render.JSON(w, r, response.Error(fmt.Sprintf("Failed to decode: %v", err)))
+			return
+		}
+		logger = logger.With(slog.Int("size", len(body.Data)))
+
+		report := r.URL.Query().Get("report") == "items"
+		results, err := handler.LoadManufacturers(body.Data, report)
+		if err != nil {
+			logger.Error("load manufacturers", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Save data failed: %v", err)))
+			return
+		}
+		logger.Debug("manufacturers saved")
+
+		if report {
+			render.JSON(w, r, response.Items(results))
+			return
+		}
+		render.JSON(w, r, response.Ok(nil))
+	}
+}
+
+func Merge(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.manufacturer")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("manufacturer service not available")
+			render.JSON(w, r, response.Error("Manufacturer service not available"))
+			return
+		}
+
+		var body entity.ManufacturerMerge
+		if err := render.Bind(r, &body); err != nil {
+			logger.Error("bind request data", sl.Err(err))
+			render.Status(r, 400)
+			render.JSON(w, r, response.Error(fmt.Sprintf("Failed to decode: %v", err)))
+			return
+		}
+
+		result, err := handler.MergeManufacturers(&body)
+		if err != nil {
+			logger.Error("merge manufacturers", sl.Err(err))
+			switch {
+			case errors.Is(err, entity.ErrNotFound):
+				render.Status(r, 404)
+			case errors.Is(err, entity.ErrInvalid):
+				render.Status(r, 400)
+			}
+			render.JSON(w, r, response.Error(fmt.Sprintf("Merge failed: %v", err)))
+			return
+		}
+
+		render.JSON(w, r, response.Ok(result))
+	}
+}
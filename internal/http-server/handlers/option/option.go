@@ -0,0 +1,57 @@
+package option
+
+import (
+	"fmt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"ocapi/entity"
+	"ocapi/internal/lib/api/response"
+	"ocapi/internal/lib/sl"
+)
+
+type Core interface {
+	LoadOptions(options []*entity.Option, report bool) ([]*entity.ItemResult, error)
+}
+
+func Save(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.option")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("option service not available")
+			render.JSON(w, r, response.Error("Option service not available"))
+			return
+		}
+
+		var body entity.OptionDataRequest
+		if err := render.Bind(r, &body); err != nil {
+			logger.Error("bind request data", sl.Err(err))
+			render.Status(r, 400)
+			render.JSON(w, r, response.Error(fmt.Sprintf("Failed to decode: %v", err)))
+			return
+		}
+		logger = logger.With(slog.Int("size", len(body.Data)))
+
+		report := r.URL.Query().Get("report") == "items"
+		results, err := handler.LoadOptions(body.Data, report)
+		if err != nil {
+			logger.Error("load options", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Save data failed: %v", err)))
+			return
+		}
+		logger.Debug("options data saved")
+
+		if report {
+			render.JSON(w, r, response.Items(results))
+			return
+		}
+		render.JSON(w, r, response.Ok(nil))
+	}
+}
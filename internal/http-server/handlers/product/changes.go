@@ -0,0 +1,74 @@
+package product
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"ocapi/entity"
+	"ocapi/internal/lib/api/response"
+	"ocapi/internal/lib/sl"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// Changes lists products modified since ?modified_since= (RFC 3339), paged with ?cursor= and ?limit=
+func Changes(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.product")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("product service not available")
+			render.JSON(w, r, response.Error("Product service not available"))
+			return
+		}
+
+		since, cursor, limit, err := parseChangesQuery(r)
+		if err != nil {
+			logger.Error("parse request", sl.Err(err))
+			render.Status(r, 400)
+			render.JSON(w, r, response.Error(fmt.Sprintf("Invalid request: %v", err)))
+			return
+		}
+
+		changes, err := handler.ProductChanges(since, cursor, limit)
+		if err != nil {
+			logger.Error("product changes", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Search failed: %v", err)))
+			return
+		}
+		logger.With(slog.Int("size", len(changes.Products))).Debug("product changes")
+
+		render.JSON(w, r, response.Ok(changes))
+	}
+}
+
+// parseChangesQuery reads modified_since, cursor and limit query parameters; all of them are optional
+func parseChangesQuery(r *http.Request) (time.Time, *entity.ProductCursor, int, error) {
+	query := r.URL.Query()
+
+	var since time.Time
+	var err error
+	if value := query.Get("modified_since"); value != "" {
+		if since, err = time.Parse(time.RFC3339, value); err != nil {
+			return since, nil, 0, fmt.Errorf("modified_since: %v", err)
+		}
+	}
+
+	var limit int
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil {
+			return since, nil, 0, fmt.Errorf("limit: %v", err)
+		}
+	}
+
+	cursor, err := entity.ParseProductCursor(query.Get("cursor"))
+	return since, cursor, limit, err
+}
@@ -1,13 +1,25 @@
 package product
 
-import "ocapi/entity"
+import (
+	"io"
+	"ocapi/entity"
+	"time"
+)
 
 type Core interface {
-	FindProduct(uid string) (interface{}, error)
-	LoadProducts(products []*entity.ProductData) error
-	LoadProductDescriptions(products []*entity.ProductDescription) error
+	FindProduct(uid string, include map[string]bool) (*entity.ProductView, error)
+	ProductChanges(since time.Time, cursor *entity.ProductCursor, limit int) (*entity.ProductChanges, error)
+	LoadProducts(products []*entity.ProductData, report bool) ([]*entity.ItemResult, error)
+	LoadProductDescriptions(products []*entity.ProductDescription, report bool) ([]*entity.ItemResult, error)
 	LoadProductImages(products []*entity.ProductImage) error
+	UploadProductImage(image *entity.ProductImageUpload, file io.Reader) error
+	CheckImages(images []*entity.ImageVersion) (*entity.ImageCheckResult, error)
 	SetProductImages(products []*entity.ProductData) error
-	LoadProductAttributes(products []*entity.ProductAttribute) error
-	LoadProductSpecial(products []*entity.ProductSpecial) error
+	LoadProductAttributes(products []*entity.ProductAttribute, report bool) ([]*entity.ItemResult, error)
+	LoadProductOptions(products []*entity.ProductOption, report bool) ([]*entity.ItemResult, error)
+	LoadProductSpecial(products []*entity.ProductSpecial, replace, report bool) ([]*entity.ItemResult, error)
+	PurgeExpiredSpecials() (*entity.SpecialPurgeResult, error)
+	LoadProductDiscounts(products []*entity.ProductDiscount, report bool) ([]*entity.ItemResult, error)
+	SetProductDownloads(products []*entity.ProductDownload) error
+	DeleteProducts(uids []string, deleteImages, report bool) ([]*entity.ItemResult, error)
 }
@@ -0,0 +1,97 @@
+package product
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"ocapi/entity"
+	"ocapi/internal/lib/api/response"
+	"ocapi/internal/lib/sl"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// Delete removes a single product by UID; ?delete_images=true also removes its unused image files
+func Delete(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.product")
+		uid := chi.URLParam(r, "uid")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			slog.String("uid", uid),
+		)
+
+		if handler == nil {
+			logger.Error("product service not available")
+			render.JSON(w, r, response.Error("Product service not available"))
+			return
+		}
+
+		deleteImages := r.URL.Query().Get("delete_images") == "true"
+		results, err := handler.DeleteProducts([]string{uid}, deleteImages, true)
+		if err != nil {
+			logger.Error("delete product", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Delete failed: %v", err)))
+			return
+		}
+
+		result := results[0]
+		if !result.Success {
+			logger.Error("delete product", slog.String("error", result.Message))
+			if result.Code == entity.ItemErrorNotFound {
+				render.Status(r, 404)
+			}
+			render.JSON(w, r, response.Error(fmt.Sprintf("Delete failed: %s", result.Message)))
+			return
+		}
+		logger.Debug("product deleted")
+
+		render.JSON(w, r, response.Ok(nil))
+	}
+}
+
+// DeleteProducts removes a list of products by UID
+func DeleteProducts(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.product")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("product service not available")
+			render.JSON(w, r, response.Error("Product service not available"))
+			return
+		}
+
+		var body entity.ProductDeleteRequest
+		if err := render.Bind(r, &body); err != nil {
+			logger.Error("bind request data", sl.Err(err))
+			render.Status(r, 400)
+			render.JSON(w, r, response.Error(fmt.Sprintf("Failed to decode: %v", err)))
+			return
+		}
+		logger = logger.With(slog.Int("size", len(body.Data)))
+
+		report := r.URL.Query().Get("report") == "items"
+		results, err := handler.DeleteProducts(body.Data, body.DeleteImages, report)
+		if err != nil {
+			logger.Error("delete products", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Delete failed: %v", err)))
+			return
+		}
+		logger.Debug("products deleted")
+
+		if report {
+			render.JSON(w, r, response.Items(results))
+			return
+		}
+		render.JSON(w, r, response.Ok(nil))
+	}
+}
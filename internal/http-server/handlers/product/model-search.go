@@ -1,9 +1,11 @@
 package product
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"ocapi/entity"
 	"ocapi/internal/lib/api/response"
 	"ocapi/internal/lib/sl"
 
@@ -29,9 +31,20 @@ func UidSearch(log *slog.Logger, handler Core) http.HandlerFunc {
 			return
 		}
 
-		product, err := handler.FindProduct(uid)
+		include, err := entity.ParseProductInclude(r.URL.Query().Get("include"))
+		if err != nil {
+			logger.Error("parse include", sl.Err(err))
+			render.Status(r, 400)
+			render.JSON(w, r, response.Error(fmt.Sprintf("Invalid request: %v", err)))
+			return
+		}
+
+		product, err := handler.FindProduct(uid, include)
 		if err != nil {
 			logger.Error("product search", sl.Err(err))
+			if errors.Is(err, entity.ErrNotFound) {
+				render.Status(r, 404)
+			}
 			render.JSON(w, r, response.Error(fmt.Sprintf("Search failed: %v", err)))
 			return
 		}
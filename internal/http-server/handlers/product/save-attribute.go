@@ -36,7 +36,8 @@ func SaveAttribute(log *slog.Logger, handler Core) http.HandlerFunc {
 		}
 		logger = logger.With(slog.Int("size", len(body.Data)))
 
-		err := handler.LoadProductAttributes(body.Data)
+		report := r.URL.Query().Get("report") == "items"
+		results, err := handler.LoadProductAttributes(body.Data, report)
 		if err != nil {
 			logger.Error("load attributes", sl.Err(err))
 			render.JSON(w, r, response.Error(fmt.Sprintf("Save data failed: %v", err)))
@@ -44,6 +45,10 @@ func SaveAttribute(log *slog.Logger, handler Core) http.HandlerFunc {
 		}
 		logger.Debug("product attributes saved")
 
+		if report {
+			render.JSON(w, r, response.Items(results))
+			return
+		}
 		render.JSON(w, r, response.Ok(nil))
 	}
 }
@@ -36,7 +36,8 @@ func SaveDescription(log *slog.Logger, handler Core) http.HandlerFunc {
 		}
 		logger = logger.With(slog.Int("size", len(body.Data)))
 
-		err := handler.LoadProductDescriptions(body.Data)
+		report := r.URL.Query().Get("report") == "items"
+		results, err := handler.LoadProductDescriptions(body.Data, report)
 		if err != nil {
 			logger.Error("load products", sl.Err(err))
 			render.JSON(w, r, response.Error(fmt.Sprintf("Save data failed: %v", err)))
@@ -44,6 +45,10 @@ func SaveDescription(log *slog.Logger, handler Core) http.HandlerFunc {
 		}
 		logger.Debug("product descriptions saved")
 
+		if report {
+			render.JSON(w, r, response.Items(results))
+			return
+		}
 		render.JSON(w, r, response.Ok(nil))
 	}
 }
@@ -81,3 +81,42 @@ func SetImages(log *slog.Logger, handler Core) http.HandlerFunc {
 		render.JSON(w, r, response.Ok(nil))
 	}
 }
+
+func CheckImages(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.product")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("product service not available")
+			render.JSON(w, r, response.Error("Product service not available"))
+			return
+		}
+
+		var body entity.ImageCheckRequest
+		if err := render.Bind(r, &body); err != nil {
+			logger.Error("bind request data", sl.Err(err))
+			render.Status(r, 400)
+			render.JSON(w, r, response.Error(fmt.Sprintf("Failed to decode: %v", err)))
+			return
+		}
+		logger = logger.With(slog.Int("size", len(body.Data)))
+
+		result, err := handler.CheckImages(body.Data)
+		if err != nil {
+			logger.Error("check images", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Check images: %v", err)))
+			return
+		}
+		logger.With(
+			slog.Int("missing", len(result.Missing)),
+			slog.Int("stale", len(result.Stale)),
+		).Debug("product images checked")
+
+		render.JSON(w, r, response.Ok(result))
+	}
+}
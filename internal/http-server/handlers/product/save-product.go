@@ -52,7 +52,8 @@ func SaveProduct(log *slog.Logger, handler Core) http.HandlerFunc {
 			return
 		}
 
-		err := handler.LoadProducts(body.Data)
+		report := r.URL.Query().Get("report") == "items"
+		results, err := handler.LoadProducts(body.Data, report)
 		if err != nil {
 			logger.Error("load products", sl.Err(err))
 			render.JSON(w, r, response.Error(fmt.Sprintf("Save data failed: %v", err)))
@@ -60,6 +61,10 @@ func SaveProduct(log *slog.Logger, handler Core) http.HandlerFunc {
 		}
 		logger.Debug("product data saved")
 
+		if report {
+			render.JSON(w, r, response.Items(results))
+			return
+		}
 		render.JSON(w, r, response.Ok(nil))
 	}
 }
@@ -34,9 +34,11 @@ func SaveSpecial(log *slog.Logger, handler Core) http.HandlerFunc {
 			render.JSON(w, r, response.Error(fmt.Sprintf("Failed to decode: %v", err)))
 			return
 		}
-		logger = logger.With(slog.Int("size", len(body.Data)))
+		replace := r.URL.Query().Get("mode") == "replace"
+		logger = logger.With(slog.Int("size", len(body.Data)), slog.Bool("replace", replace))
 
-		err := handler.LoadProductSpecial(body.Data)
+		report := r.URL.Query().Get("report") == "items"
+		results, err := handler.LoadProductSpecial(body.Data, replace, report)
 		if err != nil {
 			logger.Error("load special", sl.Err(err))
 			render.JSON(w, r, response.Error(fmt.Sprintf("Save data failed: %v", err)))
@@ -44,6 +46,36 @@ func SaveSpecial(log *slog.Logger, handler Core) http.HandlerFunc {
 		}
 		logger.Debug("product special saved")
 
+		if report {
+			render.JSON(w, r, response.Items(results))
+			return
+		}
 		render.JSON(w, r, response.Ok(nil))
 	}
 }
+
+func PurgeSpecials(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.product")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("product service not available")
+			render.JSON(w, r, response.Error("Product service not available"))
+			return
+		}
+
+		result, err := handler.PurgeExpiredSpecials()
+		if err != nil {
+			logger.Error("purge specials", sl.Err(err))
+			render.JSON(w, r, response.Error(fmt.Sprintf("Purge specials failed: %v", err)))
+			return
+		}
+
+		render.JSON(w, r, response.Ok(result))
+	}
+}
@@ -0,0 +1,127 @@
+package product
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"ocapi/entity"
+	"ocapi/internal/lib/api/response"
+	"ocapi/internal/lib/sl"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// maxFieldSize limits the size of a text field of the multipart form
+const maxFieldSize = 1024
+
+// UploadImage accepts a multipart/form-data image upload; text fields product_uid, file_uid,
+// is_main, version and sort_order must precede the "file" part, which is streamed to disk without
+// buffering, or skipped if the product already has the image with the same version
+func UploadImage(log *slog.Logger, handler Core) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mod := sl.Module("http.handlers.product")
+
+		logger := log.With(
+			mod,
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		if handler == nil {
+			logger.Error("product service not available")
+			render.JSON(w, r, response.Error("Product service not available"))
+			return
+		}
+
+		reader, err := r.MultipartReader()
+		if err != nil {
+			logger.Error("multipart request", sl.Err(err))
+			render.Status(r, 400)
+			render.JSON(w, r, response.Error(fmt.Sprintf("Failed to decode: %v", err)))
+			return
+		}
+
+		var image entity.ProductImageUpload
+		for {
+			part, err := reader.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				logger.Error("read part", sl.Err(err))
+				render.Status(r, 400)
+				render.JSON(w, r, response.Error(fmt.Sprintf("Failed to decode: %v", err)))
+				return
+			}
+
+			if part.FormName() != "file" {
+				if err = readImageField(&image, part); err != nil {
+					logger.Error("read field", sl.Err(err))
+					render.Status(r, 400)
+					render.JSON(w, r, response.Error(fmt.Sprintf("Failed to decode: %v", err)))
+					return
+				}
+				continue
+			}
+
+			if err = image.Bind(r); err != nil {
+				logger.Error("bind request data", sl.Err(err))
+				render.Status(r, 400)
+				render.JSON(w, r, response.Error(fmt.Sprintf("Failed to decode: %v", err)))
+				return
+			}
+			logger = logger.With(slog.String("product_uid", image.ProductUid), slog.String("file_uid", image.FileUid))
+
+			err = handler.UploadProductImage(&image, part)
+			if err != nil {
+				logger.Error("upload image", sl.Err(err))
+				if errors.Is(err, entity.ErrInvalid) {
+					render.Status(r, 400)
+				}
+				render.JSON(w, r, response.Error(fmt.Sprintf("Save image: %v", err)))
+				return
+			}
+			logger.Debug("product image uploaded")
+
+			render.JSON(w, r, response.Ok(nil))
+			return
+		}
+
+		logger.Error("file part missing")
+		render.Status(r, 400)
+		render.JSON(w, r, response.Error("Failed to decode: file part missing"))
+	}
+}
+
+// readImageField sets an image form field from a multipart part
+func readImageField(image *entity.ProductImageUpload, part *multipart.Part) error {
+	data, err := io.ReadAll(io.LimitReader(part, maxFieldSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxFieldSize {
+		return fmt.Errorf("field %s too long", part.FormName())
+	}
+	value := string(data)
+
+	switch part.FormName() {
+	case "product_uid":
+		image.ProductUid = value
+	case "file_uid":
+		image.FileUid = value
+	case "is_main":
+		image.IsMain, err = strconv.ParseBool(value)
+	case "version":
+		image.Version = value
+	case "sort_order":
+		image.SortOrder, err = strconv.ParseInt(value, 10, 64)
+	}
+	if err != nil {
+		return fmt.Errorf("field %s: %v", part.FormName(), err)
+	}
+	return nil
+}
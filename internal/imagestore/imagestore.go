@@ -0,0 +1,67 @@
+// Package imagestore keeps product image files in the OpenCart images directory, either on the
+// local filesystem or in an S3-compatible bucket the web server reads from.
+package imagestore
+
+import (
+	"fmt"
+	"io"
+	"ocapi/internal/config"
+)
+
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+// Store saves, finds and removes image files by name; names are relative to the images directory
+type Store interface {
+	// Put writes the file, replacing an existing one; readers never see a partially written file
+	Put(name string, src io.ReadSeeker) error
+	// Exists reports whether the file is present
+	Exists(name string) (bool, error)
+	// Find returns the name of a file named {base}.{ext}, empty if there is none
+	Find(base string) (string, error)
+	// List returns the names of all stored files
+	List() ([]string, error)
+	// Match returns the names of files starting with the prefix, e.g. catalog/product/uid-
+	Match(prefix string) ([]string, error)
+	// Delete removes the file; a missing file is not an error
+	Delete(name string) error
+	// Move renames the file, replacing the target and creating its folders as needed;
+	// a missing file is not an error
+	Move(name, target string) error
+}
+
+// NewCache creates the store of the OpenCart image cache (DIR_IMAGE/cache/) with the storage type of the images;
+// nil if the cache location is not configured
+func NewCache(conf *config.Config) (Store, error) {
+	cache := conf.Images.Cache
+	switch conf.Images.Storage {
+	case StorageLocal, "":
+		if cache.Path == "" {
+			return nil, nil
+		}
+		return NewLocal(cache.Path), nil
+	case StorageS3:
+		if cache.Prefix == "" {
+			return nil, nil
+		}
+		s3 := conf.Images.S3
+		return NewS3(s3.Endpoint, s3.Region, s3.Bucket, cache.Prefix, s3.AccessKey, s3.SecretKey, s3.PathStyle)
+	default:
+		return nil, fmt.Errorf("unknown image storage: %s", conf.Images.Storage)
+	}
+}
+
+// New creates the image store selected in the images configuration section
+func New(conf *config.Config) (Store, error) {
+	switch conf.Images.Storage {
+	case StorageLocal, "":
+		return NewLocal(conf.Images.Path), nil
+	case StorageS3:
+		s3 := conf.Images.S3
+		return NewS3(s3.Endpoint, s3.Region, s3.Bucket, s3.Prefix, s3.AccessKey, s3.SecretKey, s3.PathStyle)
+	default:
+		return nil, fmt.Errorf("unknown image storage: %s", conf.Images.Storage)
+	}
+}
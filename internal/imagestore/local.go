@@ -0,0 +1,137 @@
+package imagestore
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// tempPrefix marks files being written; they are not listed
+const tempPrefix = ".upload-"
+
+// Local keeps image files in a directory of the local filesystem
+type Local struct {
+	path string
+}
+
+func NewLocal(path string) *Local {
+	return &Local{path: path}
+}
+
+func (l *Local) filePath(name string) string {
+	return filepath.Join(l.path, filepath.Clean("/"+name))
+}
+
+// Put writes the content to a temporary file in the same directory and renames it
+func (l *Local) Put(name string, src io.ReadSeeker) error {
+	path := l.filePath(name)
+	tmp, err := os.CreateTemp(filepath.Dir(path), tempPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %v", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err = io.Copy(tmp, src); err != nil {
+		return fmt.Errorf("write: %v", err)
+	}
+	if err = tmp.Chmod(0644); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("write: %v", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename: %v", err)
+	}
+	return nil
+}
+
+func (l *Local) Exists(name string) (bool, error) {
+	_, err := os.Stat(l.filePath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (l *Local) Find(base string) (string, error) {
+	pattern := filepath.Join(l.path, filepath.Base(base)+".*")
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", fmt.Errorf("glob %s: %v", pattern, err)
+	}
+	if len(matches) == 0 {
+		return "", nil
+	}
+	return filepath.Base(matches[0]), nil
+}
+
+// List walks the directory tree; files being written are skipped
+func (l *Local) List() ([]string, error) {
+	var names []string
+	err := filepath.Walk(l.path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), tempPrefix) {
+			return nil
+		}
+		name, err := filepath.Rel(l.path, path)
+		if err != nil {
+			return err
+		}
+		names = append(names, filepath.ToSlash(name))
+		return nil
+	})
+	return names, err
+}
+
+// Match reads the folder of the prefix; files in subfolders are not matched
+func (l *Local) Match(prefix string) ([]string, error) {
+	dir, namePrefix := path.Split(prefix)
+	entries, err := os.ReadDir(l.filePath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), namePrefix) || strings.HasPrefix(entry.Name(), tempPrefix) {
+			continue
+		}
+		names = append(names, path.Join(dir, entry.Name()))
+	}
+	return names, nil
+}
+
+func (l *Local) Delete(name string) error {
+	err := os.Remove(l.filePath(name))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) Move(name, target string) error {
+	path := l.filePath(target)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	err := os.Rename(l.filePath(name), path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
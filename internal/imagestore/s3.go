@@ -0,0 +1,275 @@
+package imagestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// S3 keeps image files in a bucket of an S3-compatible object storage under a key prefix
+type S3 struct {
+	endpoint  *url.URL
+	bucket    string
+	prefix    string
+	pathStyle bool
+	signer    *signer
+	client    *http.Client
+}
+
+// NewS3 creates the store for the bucket at the endpoint, e.g. https://s3.eu-central-1.amazonaws.com;
+// with pathStyle the bucket is addressed as {endpoint}/{bucket}, as most self-hosted storages expect,
+// otherwise as {bucket}.{endpoint host}.
+func NewS3(endpoint, region, bucket, prefix, accessKey, secretKey string, pathStyle bool) (*S3, error) {
+	if endpoint == "" || bucket == "" {
+		return nil, fmt.Errorf("s3 endpoint and bucket must be set")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("s3 endpoint: %v", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint must be an absolute URL: %s", endpoint)
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	prefix = strings.TrimPrefix(prefix, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &S3{
+		endpoint:  u,
+		bucket:    bucket,
+		prefix:    prefix,
+		pathStyle: pathStyle,
+		signer:    &signer{accessKey: accessKey, secretKey: secretKey, region: region},
+		client:    &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// objectUrl builds the URL of an object key; an empty key addresses the bucket
+func (s *S3) objectUrl(key string, query url.Values) *url.URL {
+	u := *s.endpoint
+	base := strings.TrimSuffix(u.Path, "/")
+	if s.pathStyle {
+		base += "/" + s.bucket
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	u.Path = base + "/" + key
+	if key == "" && s.pathStyle {
+		u.Path = base
+	}
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = canonicalQuery(query)
+	return &u
+}
+
+func (s *S3) key(name string) string {
+	return s.prefix + strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+func (s *S3) do(method string, u *url.URL, body io.ReadSeeker, header http.Header) (*http.Response, error) {
+	payloadHash := emptyPayload
+	var size int64
+	if body != nil {
+		hasher := sha256.New()
+		n, err := io.Copy(hasher, body)
+		if err != nil {
+			return nil, fmt.Errorf("read: %v", err)
+		}
+		if _, err = body.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		payloadHash = hex.EncodeToString(hasher.Sum(nil))
+		size = n
+	}
+
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		// an empty body must be http.NoBody, or the length is not sent and the storage rejects the request
+		req.Body = http.NoBody
+		if size > 0 {
+			req.Body = io.NopCloser(body)
+			req.ContentLength = size
+		}
+	}
+	s.signer.sign(req, payloadHash, time.Now())
+
+	return s.client.Do(req)
+}
+
+// s3Error is the error document returned by the storage
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func responseError(method string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var e s3Error
+	if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Errorf("s3 %s: %s: %s", method, e.Code, e.Message)
+	}
+	return fmt.Errorf("s3 %s: %s", method, resp.Status)
+}
+
+func (s *S3) Put(name string, src io.ReadSeeker) error {
+	header := http.Header{}
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(http.MethodPut, s.objectUrl(s.key(name), nil), src, header)
+	if err != nil {
+		return fmt.Errorf("s3 put: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError("put", resp)
+	}
+	return nil
+}
+
+func (s *S3) Exists(name string) (bool, error) {
+	resp, err := s.do(http.MethodHead, s.objectUrl(s.key(name), nil), nil, nil)
+	if err != nil {
+		return false, fmt.Errorf("s3 head: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, responseError("head", resp)
+	}
+}
+
+// listResult is the response of ListObjectsV2
+type listResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// list returns object names under the store prefix starting with the given name prefix,
+// reading at most limit names (0 = all pages)
+func (s *S3) list(namePrefix string, limit int) ([]string, error) {
+	var names []string
+	token := ""
+	for {
+		query := url.Values{
+			"list-type": {"2"},
+			"prefix":    {s.prefix + namePrefix},
+		}
+		if limit > 0 {
+			query.Set("max-keys", fmt.Sprint(limit))
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(http.MethodGet, s.objectUrl("", query), nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("s3 list: %v", err)
+		}
+		var result listResult
+		if resp.StatusCode != http.StatusOK {
+			err = responseError("list", resp)
+		} else {
+			err = xml.NewDecoder(resp.Body).Decode(&result)
+		}
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range result.Contents {
+			names = append(names, strings.TrimPrefix(object.Key, s.prefix))
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" || (limit > 0 && len(names) >= limit) {
+			return names, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (s *S3) Find(base string) (string, error) {
+	names, err := s.list(path.Base(base)+".", 1)
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	return names[0], nil
+}
+
+func (s *S3) List() ([]string, error) {
+	return s.list("", 0)
+}
+
+func (s *S3) Match(prefix string) ([]string, error) {
+	return s.list(strings.TrimPrefix(prefix, "/"), 0)
+}
+
+func (s *S3) Delete(name string) error {
+	resp, err := s.do(http.MethodDelete, s.objectUrl(s.key(name), nil), nil, nil)
+	if err != nil {
+		return fmt.Errorf("s3 delete: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return responseError("delete", resp)
+	}
+	return nil
+}
+
+// Move copies the object to the target key and deletes the source; S3 has no rename
+func (s *S3) Move(name, target string) error {
+	header := http.Header{}
+	header.Set("X-Amz-Copy-Source", uriEncode("/"+s.bucket+"/"+s.key(name), false))
+	resp, err := s.do(http.MethodPut, s.objectUrl(s.key(target), nil), nil, header)
+	if err != nil {
+		return fmt.Errorf("s3 copy: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil
+	default:
+		return responseError("copy", resp)
+	}
+	// a copy may fail after the 200 status is sent; the error is then in the body
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return fmt.Errorf("s3 copy: %v", err)
+	}
+	var e s3Error
+	if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Errorf("s3 copy: %s: %s", e.Code, e.Message)
+	}
+	return s.Delete(name)
+}
@@ -0,0 +1,233 @@
+package imagestore
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 serves a path-style bucket in memory; listings are returned in pages of pageSize keys
+type fakeS3 struct {
+	t        *testing.T
+	bucket   string
+	pageSize int
+
+	mu      sync.Mutex
+	objects map[string][]byte
+	pages   int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), signAlgorithm+" ") {
+		f.t.Errorf("%s %s: request not signed", r.Method, r.URL.Path)
+	}
+	if r.Header.Get("X-Amz-Content-Sha256") == "" {
+		f.t.Errorf("%s %s: payload hash missing", r.Method, r.URL.Path)
+	}
+
+	bucketPath := "/" + f.bucket
+	if r.URL.Path == bucketPath && r.Method == http.MethodGet {
+		f.list(w, r)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, bucketPath+"/")
+	if !ok {
+		http.Error(w, "no such bucket", http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		if source := r.Header.Get("X-Amz-Copy-Source"); source != "" {
+			source, _ = url.PathUnescape(source)
+			data, found := f.objects[strings.TrimPrefix(source, bucketPath+"/")]
+			if !found {
+				writeS3Error(w, http.StatusNotFound, "NoSuchKey")
+				return
+			}
+			f.objects[key] = data
+			_, _ = fmt.Fprint(w, "<CopyObjectResult></CopyObjectResult>")
+			return
+		}
+		if r.ContentLength < 0 {
+			writeS3Error(w, http.StatusLengthRequired, "MissingContentLength")
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			f.t.Errorf("read body: %v", err)
+		}
+		if got := hashHex(data); got != r.Header.Get("X-Amz-Content-Sha256") {
+			writeS3Error(w, http.StatusBadRequest, "XAmzContentSHA256Mismatch")
+			return
+		}
+		f.objects[key] = data
+	case http.MethodHead:
+		if _, found := f.objects[key]; !found {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("list-type") != "2" {
+		writeS3Error(w, http.StatusBadRequest, "InvalidArgument")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pages++
+
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, query.Get("prefix")) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	start := 0
+	if token := query.Get("continuation-token"); token != "" {
+		start, _ = strconv.Atoi(token)
+	}
+	pageSize := f.pageSize
+	if maxKeys, err := strconv.Atoi(query.Get("max-keys")); err == nil && maxKeys < pageSize {
+		pageSize = maxKeys
+	}
+	end := min(start+pageSize, len(keys))
+
+	var result listResult
+	for _, key := range keys[start:end] {
+		result.Contents = append(result.Contents, struct {
+			Key string `xml:"Key"`
+		}{Key: key})
+	}
+	if end < len(keys) {
+		result.IsTruncated = true
+		result.NextContinuationToken = strconv.Itoa(end)
+	}
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func newTestS3(t *testing.T) (*S3, *fakeS3) {
+	fake := &fakeS3{t: t, bucket: "images", pageSize: 2, objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	store, err := NewS3(server.URL, "", "images", "catalog/product", "key", "secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, fake
+}
+
+func TestS3RoundTrip(t *testing.T) {
+	store, fake := newTestS3(t)
+
+	files := map[string]string{
+		"a.jpg": "image a",
+		"b.png": "image b",
+		"c.gif": "image c",
+		"d.jpg": "image d",
+		"e.jpg": "",
+	}
+	for name, content := range files {
+		if err := store.Put(name, strings.NewReader(content)); err != nil {
+			t.Fatalf("put %s: %v", name, err)
+		}
+	}
+	for name, content := range files {
+		got, found := fake.objects["catalog/product/"+name]
+		if !found || !bytes.Equal(got, []byte(content)) {
+			t.Errorf("object %s = %q, %v; want %q", name, got, found, content)
+		}
+	}
+
+	names, err := store.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	want := []string{"a.jpg", "b.png", "c.gif", "d.jpg", "e.jpg"}
+	if !slices.Equal(names, want) {
+		t.Errorf("list = %v, want %v", names, want)
+	}
+	if fake.pages != 3 {
+		t.Errorf("list read %d pages, want 3", fake.pages)
+	}
+
+	name, err := store.Find("b")
+	if err != nil || name != "b.png" {
+		t.Errorf("find b = %q, %v; want b.png", name, err)
+	}
+	name, err = store.Find("x")
+	if err != nil || name != "" {
+		t.Errorf("find x = %q, %v; want none", name, err)
+	}
+
+	if err = store.Move("a.jpg", "f.jpg"); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	exists, err := store.Exists("a.jpg")
+	if err != nil || exists {
+		t.Errorf("moved source exists = %v, %v", exists, err)
+	}
+	exists, err = store.Exists("f.jpg")
+	if err != nil || !exists {
+		t.Errorf("move target exists = %v, %v", exists, err)
+	}
+	if got := string(fake.objects["catalog/product/f.jpg"]); got != "image a" {
+		t.Errorf("move target content = %q", got)
+	}
+	if err = store.Move("missing.jpg", "g.jpg"); err != nil {
+		t.Errorf("move of a missing file: %v", err)
+	}
+
+	if err = store.Delete("f.jpg"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err = store.Delete("f.jpg"); err != nil {
+		t.Errorf("delete of a missing file: %v", err)
+	}
+	names, err = store.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	want = []string{"b.png", "c.gif", "d.jpg", "e.jpg"}
+	if !slices.Equal(names, want) {
+		t.Errorf("list after delete = %v, want %v", names, want)
+	}
+}
+
+func TestS3Error(t *testing.T) {
+	store, _ := newTestS3(t)
+	store.bucket = "other"
+
+	err := store.Put("a.jpg", strings.NewReader("image a"))
+	if err == nil {
+		t.Fatal("put to a missing bucket succeeded")
+	}
+}
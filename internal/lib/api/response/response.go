@@ -1,6 +1,10 @@
 package response
 
-import "ocapi/internal/lib/clock"
+import (
+	"fmt"
+	"ocapi/entity"
+	"ocapi/internal/lib/clock"
+)
 
 type Response struct {
 	Data          interface{} `json:"data,omitempty"`
@@ -25,3 +29,22 @@ func Error(message string) Response {
 		Timestamp:     clock.Now(),
 	}
 }
+
+// Items returns per-item results of a bulk request; success is false if any item failed
+func Items(results []*entity.ItemResult) Response {
+	failed := 0
+	for _, result := range results {
+		if !result.Success {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return Ok(results)
+	}
+	return Response{
+		Data:          results,
+		Success:       false,
+		StatusMessage: fmt.Sprintf("Failed %d of %d items", failed, len(results)),
+		Timestamp:     clock.Now(),
+	}
+}
@@ -0,0 +1,86 @@
+package imaging
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+)
+
+// readOrientation returns the EXIF orientation of a JPEG stream, 1 if absent or unreadable
+func readOrientation(r io.Reader) int {
+	br := bufio.NewReader(r)
+	var marker [2]byte
+	if _, err := io.ReadFull(br, marker[:]); err != nil || marker[0] != 0xFF || marker[1] != 0xD8 {
+		return 1
+	}
+
+	for {
+		if _, err := io.ReadFull(br, marker[:]); err != nil || marker[0] != 0xFF {
+			return 1
+		}
+		// start of scan or end of image: no more metadata segments
+		if marker[1] == 0xDA || marker[1] == 0xD9 {
+			return 1
+		}
+		var size [2]byte
+		if _, err := io.ReadFull(br, size[:]); err != nil {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(size[:])) - 2
+		if length < 0 {
+			return 1
+		}
+		if marker[1] != 0xE1 {
+			if _, err := br.Discard(length); err != nil {
+				return 1
+			}
+			continue
+		}
+
+		segment := make([]byte, length)
+		if _, err := io.ReadFull(br, segment); err != nil {
+			return 1
+		}
+		if orientation, ok := exifOrientation(segment); ok {
+			return orientation
+		}
+	}
+}
+
+// exifOrientation reads the orientation tag from the IFD0 of an APP1 Exif segment
+func exifOrientation(segment []byte) (int, bool) {
+	if len(segment) < 14 || string(segment[:6]) != "Exif\x00\x00" {
+		return 0, false
+	}
+	tiff := segment[6:]
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, false
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset+2 > len(tiff) {
+		return 0, false
+	}
+	count := int(order.Uint16(tiff[offset : offset+2]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0, false
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if orientation >= 1 && orientation <= 8 {
+				return orientation, true
+			}
+			return 0, false
+		}
+	}
+	return 0, false
+}
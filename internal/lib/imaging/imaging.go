@@ -0,0 +1,130 @@
+package imaging
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// ErrFormat is returned for content that is not a JPEG, PNG or GIF image
+var ErrFormat = errors.New("unsupported image format")
+
+// ErrTooLarge is returned for images with more pixels than MaxPixels
+var ErrTooLarge = errors.New("image too large")
+
+// MaxPixels limits the size of images decoded by Normalize, as decoding allocates 4 bytes per pixel
+const MaxPixels = 50_000_000
+
+// Extensions maps supported formats to file extensions
+var Extensions = map[string]string{
+	"jpeg": ".jpg",
+	"png":  ".png",
+	"gif":  ".gif",
+}
+
+// Info describes an image without decoding its pixels
+type Info struct {
+	Format      string // jpeg, png or gif
+	Width       int
+	Height      int
+	Orientation int // EXIF orientation 1..8, 1 if absent
+}
+
+// Inspect reads the image header and, for JPEG, the EXIF orientation
+func Inspect(r io.ReadSeeker) (Info, error) {
+	config, format, err := image.DecodeConfig(r)
+	if err != nil {
+		return Info{}, ErrFormat
+	}
+	if _, ok := Extensions[format]; !ok {
+		return Info{}, ErrFormat
+	}
+	info := Info{Format: format, Width: config.Width, Height: config.Height, Orientation: 1}
+
+	if format == "jpeg" {
+		if _, err = r.Seek(0, io.SeekStart); err != nil {
+			return info, err
+		}
+		info.Orientation = readOrientation(r)
+	}
+	return info, nil
+}
+
+// NeedsNormalize reports whether the image must be rotated or downscaled to fit the limits;
+// a zero limit means no limit. GIF images are kept as they are to preserve animation.
+func (i Info) NeedsNormalize(maxWidth, maxHeight int) bool {
+	if i.Format == "gif" {
+		return false
+	}
+	width, height := i.Width, i.Height
+	if i.Orientation >= 5 {
+		width, height = height, width
+	}
+	return i.Orientation > 1 || (maxWidth > 0 && width > maxWidth) || (maxHeight > 0 && height > maxHeight)
+}
+
+// Normalize decodes the image, applies the EXIF orientation and downscales it to fit the limits;
+// images with more than MaxPixels pixels are rejected without decoding
+func Normalize(r io.Reader, info Info, maxWidth, maxHeight int) (image.Image, error) {
+	if int64(info.Width)*int64(info.Height) > MaxPixels {
+		return nil, ErrTooLarge
+	}
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return nil, ErrFormat
+	}
+	img := orient(toRGBA(src), info.Orientation)
+	return fit(img, maxWidth, maxHeight), nil
+}
+
+// Thumbnail decodes the image and scales it to fit width x height keeping the aspect ratio, centered on
+// a canvas of exactly that size: transparent for PNG and white otherwise, as OpenCart resizes cached images
+func Thumbnail(r io.Reader, format string, width, height int) (image.Image, error) {
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return nil, ErrFormat
+	}
+	img := toRGBA(src)
+
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	scale := min(float64(width)/float64(w), float64(height)/float64(h))
+	dw := max(1, int(float64(w)*scale))
+	dh := max(1, int(float64(h)*scale))
+	if dw != w || dh != h {
+		img = resize(img, dw, dh)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	if format != "png" {
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	}
+	offset := image.Pt((width-dw)/2, (height-dh)/2)
+	draw.Draw(dst, img.Rect.Add(offset), img, image.Point{}, draw.Over)
+	return dst, nil
+}
+
+// Encode writes the image in the given format; quality applies to JPEG
+func Encode(w io.Writer, img image.Image, format string, quality int) error {
+	switch format {
+	case "jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	case "png":
+		return png.Encode(w, img)
+	case "gif":
+		return gif.Encode(w, img, nil)
+	}
+	return ErrFormat
+}
+
+// toRGBA converts any image into an RGBA image with origin at 0,0
+func toRGBA(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+	return dst
+}
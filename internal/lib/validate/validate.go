@@ -40,7 +40,7 @@ func Struct(s interface{}) error {
 			}
 			message += fmt.Sprintf("%s %s", fieldErr.Field(), fieldErr.Tag())
 		}
-		return fmt.Errorf(message)
+		return errors.New(message)
 	} else if errors.As(err, &invalidValidationError) {
 		return fmt.Errorf("invalid validation error: %w", err)
 	} else {